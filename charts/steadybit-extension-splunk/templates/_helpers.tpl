@@ -3,3 +3,14 @@
 {{- define "splunk.secret.name" -}}
 {{- default "steadybit-extension-splunk" .Values.splunk.existingSecret -}}
 {{- end -}}
+
+{{/*
+Renders a map as comma separated key:value pairs, the format expected for map configuration values.
+*/}}
+{{- define "splunk.keyValueList" -}}
+{{- $pairs := list -}}
+{{- range $key, $value := . -}}
+{{- $pairs = append $pairs (printf "%s:%s" $key $value) -}}
+{{- end -}}
+{{- join "," $pairs -}}
+{{- end -}}
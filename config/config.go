@@ -5,21 +5,87 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog/log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 )
 
 // Specification is the configuration specification for the extension. Configuration values can be applied
 // through environment variables. Learn more through the documentation of the envconfig package.
 // https://github.com/kelseyhightower/envconfig
 type Specification struct {
-	AccessToken                         string   `json:"accessToken" split_words:"true" required:"true"`
-	ApiBaseUrl                          string   `json:"apiBaseUrl" split_words:"true" required:"true"`
-	IngestBaseUrl                       string   `json:"ingestBaseUrl" split_words:"true" required:"true"`
-	DiscoveryAttributesExcludesDetector []string `json:"discoveryAttributesExcludesDetector" split_words:"true" required:"false"`
-	DiscoveryAttributesExcludesSLO      []string `json:"discoveryAttributesExcludesSLO" split_words:"true" required:"false"`
+	AccessToken                           string            `json:"accessToken" split_words:"true" required:"true"`
+	ApiBaseUrl                            string            `json:"apiBaseUrl" split_words:"true" required:"true"`
+	IngestBaseUrl                         string            `json:"ingestBaseUrl" split_words:"true" required:"true"`
+	StreamBaseUrl                         string            `json:"streamBaseUrl" split_words:"true" required:"false"`
+	DiscoveryAttributesExcludesDetector   []string          `json:"discoveryAttributesExcludesDetector" split_words:"true" required:"false"`
+	DiscoveryAttributesExcludesSLO        []string          `json:"discoveryAttributesExcludesSLO" split_words:"true" required:"false"`
+	DiscoveryAttributesExcludesMutingRule []string          `json:"discoveryAttributesExcludesMutingRule" split_words:"true" required:"false"`
+	DiscoveryPageSize                     int               `json:"discoveryPageSize" split_words:"true" required:"false" default:"100"`
+	DiscoveryMaxResults                   int               `json:"discoveryMaxResults" split_words:"true" required:"false" default:"10000"`
+	ApiRequestTimeout                     time.Duration     `json:"apiRequestTimeout" split_words:"true" required:"false" default:"30s"`
+	ApiRetryCount                         int               `json:"apiRetryCount" split_words:"true" required:"false" default:"3"`
+	ApiRetryWaitTime                      time.Duration     `json:"apiRetryWaitTime" split_words:"true" required:"false" default:"500ms"`
+	ApiRetryMaxWaitTime                   time.Duration     `json:"apiRetryMaxWaitTime" split_words:"true" required:"false" default:"30s"`
+	CheckApiErrorTolerance                int               `json:"checkApiErrorTolerance" split_words:"true" required:"false" default:"2"`
+	HealthFailureThreshold                int               `json:"healthFailureThreshold" split_words:"true" required:"false" default:"3"`
+	EventQueueSize                        int               `json:"eventQueueSize" split_words:"true" required:"false" default:"1000"`
+	EventBatchSize                        int               `json:"eventBatchSize" split_words:"true" required:"false" default:"50"`
+	EventFlushInterval                    time.Duration     `json:"eventFlushInterval" split_words:"true" required:"false" default:"2s"`
+	EventDeliveryRetries                  int               `json:"eventDeliveryRetries" split_words:"true" required:"false" default:"3"`
+	EventSpoolDirectory                   string            `json:"eventSpoolDirectory" split_words:"true" required:"false"`
+	EventSpoolMaxBytes                    int64             `json:"eventSpoolMaxBytes" split_words:"true" required:"false" default:"52428800"`
+	ObservabilityEventsEnabled            bool              `json:"observabilityEventsEnabled" split_words:"true" required:"false" default:"true"`
+	HecEnabled                            bool              `json:"hecEnabled" split_words:"true" required:"false" default:"false"`
+	HecBaseUrl                            string            `json:"hecBaseUrl" split_words:"true" required:"false"`
+	HecToken                              string            `json:"hecToken" split_words:"true" required:"false"`
+	HecIndex                              string            `json:"hecIndex" split_words:"true" required:"false"`
+	HecSourcetype                         string            `json:"hecSourcetype" split_words:"true" required:"false" default:"steadybit:event"`
+	TraceExportEnabled                    bool              `json:"traceExportEnabled" split_words:"true" required:"false" default:"false"`
+	MetricExportEnabled                   bool              `json:"metricExportEnabled" split_words:"true" required:"false" default:"false"`
+	DimensionMappingFile                  string            `json:"dimensionMappingFile" split_words:"true" required:"false"`
+	StepExecutionTtl                      time.Duration     `json:"stepExecutionTtl" split_words:"true" required:"false" default:"24h"`
+	StepExecutionMaxEntries               int               `json:"stepExecutionMaxEntries" split_words:"true" required:"false" default:"10000"`
+	EventCategory                         string            `json:"eventCategory" split_words:"true" required:"false" default:"USER_DEFINED"`
+	EventTypeExperimentStarted            string            `json:"eventTypeExperimentStarted" split_words:"true" required:"false" default:"Steadybit_Event"`
+	EventTypeExperimentCompleted          string            `json:"eventTypeExperimentCompleted" split_words:"true" required:"false" default:"Steadybit_Event"`
+	EventTypeStepStarted                  string            `json:"eventTypeStepStarted" split_words:"true" required:"false" default:"Steadybit_Event"`
+	EventTypeStepCompleted                string            `json:"eventTypeStepCompleted" split_words:"true" required:"false" default:"Steadybit_Event"`
+	EventTypeTargetStarted                string            `json:"eventTypeTargetStarted" split_words:"true" required:"false" default:"Steadybit_Event"`
+	EventTypeTargetCompleted              string            `json:"eventTypeTargetCompleted" split_words:"true" required:"false" default:"Steadybit_Event"`
+	EventStaticProperties                 map[string]string `json:"eventStaticProperties" split_words:"true" required:"false"`
+	EventStaticDimensions                 map[string]string `json:"eventStaticDimensions" split_words:"true" required:"false"`
+	EventRedactionKeys                    []string          `json:"eventRedactionKeys" split_words:"true" required:"false"`
+	EventRedactionValues                  []string          `json:"eventRedactionValues" split_words:"true" required:"false"`
+	EventRedactionMode                    string            `json:"eventRedactionMode" split_words:"true" required:"false" default:"hash"`
+	EventFilterIncludeEnvironments        []string          `json:"eventFilterIncludeEnvironments" split_words:"true" required:"false"`
+	EventFilterExcludeEnvironments        []string          `json:"eventFilterExcludeEnvironments" split_words:"true" required:"false"`
+	EventFilterIncludeTeams               []string          `json:"eventFilterIncludeTeams" split_words:"true" required:"false"`
+	EventFilterExcludeTeams               []string          `json:"eventFilterExcludeTeams" split_words:"true" required:"false"`
+	EventFilterIncludeExperimentKeys      []string          `json:"eventFilterIncludeExperimentKeys" split_words:"true" required:"false"`
+	EventFilterExcludeExperimentKeys      []string          `json:"eventFilterExcludeExperimentKeys" split_words:"true" required:"false"`
+	EventFilterIncludeActionIds           []string          `json:"eventFilterIncludeActionIds" split_words:"true" required:"false"`
+	EventFilterExcludeActionIds           []string          `json:"eventFilterExcludeActionIds" split_words:"true" required:"false"`
+	StartupValidation                     string            `json:"startupValidation" split_words:"true" required:"false" default:"warn"`
 }
 
+const (
+	// StartupValidationOff skips the validation of urls, connectivity and token permissions.
+	StartupValidationOff = "off"
+	// StartupValidationWarn logs validation problems and starts the extension in a degraded state.
+	StartupValidationWarn = "warn"
+	// StartupValidationFail terminates the extension on validation problems.
+	StartupValidationFail = "fail"
+)
+
+// eventCategories are the categories accepted for custom events by Splunk Observability Cloud.
+var eventCategories = []string{"USER_DEFINED", "ALERT", "AUDIT", "JOB", "COLLECTD", "SERVICE_DISCOVERY", "EXCEPTION", "AGENT"}
+
 var (
 	Config Specification
 )
@@ -32,5 +98,90 @@ func ParseConfiguration() {
 }
 
 func ValidateConfiguration() {
-	// You may optionally validate the configuration here.
+	switch Config.StartupValidation {
+	case StartupValidationOff, StartupValidationWarn, StartupValidationFail:
+	default:
+		log.Fatal().Msgf("Invalid startup validation mode '%s', expected one of %s, %s or %s.", Config.StartupValidation, StartupValidationOff, StartupValidationWarn, StartupValidationFail)
+	}
+
+	// Unlike unreachable endpoints, an invalid event configuration can't recover at runtime, so it is never skipped.
+	if err := errors.Join(validateEventConfiguration(Config)...); err != nil {
+		log.Fatal().Err(err).Msg("Invalid event configuration.")
+	}
+
+	errs := []error{
+		validateBaseUrl("STEADYBIT_EXTENSION_API_BASE_URL", Config.ApiBaseUrl),
+		validateBaseUrl("STEADYBIT_EXTENSION_INGEST_BASE_URL", Config.IngestBaseUrl),
+	}
+	if Config.HecEnabled {
+		errs = append(errs, validateBaseUrl("STEADYBIT_EXTENSION_HEC_BASE_URL", Config.HecBaseUrl))
+		if Config.HecToken == "" {
+			errs = append(errs, errors.New("STEADYBIT_EXTENSION_HEC_TOKEN is required to forward events to the HTTP Event Collector"))
+		}
+	}
+	// The urls are validated even if the startup validation is off, which only skips the connectivity checks.
+	reportValidation(errs)
+}
+
+// validateEventConfiguration checks the category and the event types of the lifecycle phases. Empty event types fall
+// back to the default event type.
+func validateEventConfiguration(spec Specification) []error {
+	var errs []error
+	if !slices.Contains(eventCategories, spec.EventCategory) {
+		errs = append(errs, fmt.Errorf("STEADYBIT_EXTENSION_EVENT_CATEGORY '%s' must be one of %s", spec.EventCategory, strings.Join(eventCategories, ", ")))
+	}
+	eventTypes := []struct {
+		name  string
+		value string
+	}{
+		{"STEADYBIT_EXTENSION_EVENT_TYPE_EXPERIMENT_STARTED", spec.EventTypeExperimentStarted},
+		{"STEADYBIT_EXTENSION_EVENT_TYPE_EXPERIMENT_COMPLETED", spec.EventTypeExperimentCompleted},
+		{"STEADYBIT_EXTENSION_EVENT_TYPE_STEP_STARTED", spec.EventTypeStepStarted},
+		{"STEADYBIT_EXTENSION_EVENT_TYPE_STEP_COMPLETED", spec.EventTypeStepCompleted},
+		{"STEADYBIT_EXTENSION_EVENT_TYPE_TARGET_STARTED", spec.EventTypeTargetStarted},
+		{"STEADYBIT_EXTENSION_EVENT_TYPE_TARGET_COMPLETED", spec.EventTypeTargetCompleted},
+	}
+	for _, eventType := range eventTypes {
+		if eventType.value != "" && strings.TrimSpace(eventType.value) != eventType.value {
+			errs = append(errs, fmt.Errorf("%s '%s' must not start or end with whitespace", eventType.name, eventType.value))
+		}
+	}
+	return errs
+}
+
+// ReportStartupValidation logs the given validation errors of the connectivity checks and terminates the extension if
+// the startup validation is configured to fail hard. Nothing is reported if the startup validation is off.
+func ReportStartupValidation(errs []error) {
+	if Config.StartupValidation == StartupValidationOff {
+		return
+	}
+	reportValidation(errs)
+}
+
+// reportValidation logs the given validation errors and terminates the extension if the startup validation is
+// configured to fail hard. Nil errors are ignored.
+func reportValidation(errs []error) {
+	err := errors.Join(errs...)
+	if err == nil {
+		return
+	}
+
+	if Config.StartupValidation == StartupValidationFail {
+		log.Fatal().Err(err).Msg("Startup validation failed.")
+	}
+	log.Warn().Err(err).Msg("Startup validation failed, the extension starts in a degraded state.")
+}
+
+func validateBaseUrl(name string, value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%s '%s' is not a valid url: %w", name, value, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%s '%s' must use the http or https scheme", name, value)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%s '%s' is missing a host", name, value)
+	}
+	return nil
 }
@@ -0,0 +1,50 @@
+// config_test.go
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateBaseUrl(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "https", value: "https://api.us1.signalfx.com/"},
+		{name: "http with port", value: "http://localhost:8080"},
+		{name: "empty", value: "", wantErr: "scheme"},
+		{name: "missing scheme", value: "api.us1.signalfx.com", wantErr: "scheme"},
+		{name: "unsupported scheme", value: "ftp://api.us1.signalfx.com", wantErr: "scheme"},
+		{name: "missing host", value: "https://", wantErr: "host"},
+		{name: "unparsable", value: "https://api.us1.signalfx.com:port", wantErr: "not a valid url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBaseUrl("URL", tt.value)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateBaseUrl(%q) returned error: %v", tt.value, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validateBaseUrl(%q) = %v; want error containing %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEventConfiguration(t *testing.T) {
+	valid := Specification{EventCategory: "USER_DEFINED", EventTypeExperimentStarted: "chaos.experiment.started"}
+	if errs := validateEventConfiguration(valid); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	invalid := Specification{EventCategory: "CHAOS", EventTypeStepCompleted: "chaos.step.completed "}
+	errs := validateEventConfiguration(invalid)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "STEADYBIT_EXTENSION_EVENT_CATEGORY") || !strings.Contains(errs[1].Error(), "STEADYBIT_EXTENSION_EVENT_TYPE_STEP_COMPLETED") {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+const (
+	contentType         = "Content-Type"
+	applicationJsonType = "application/json"
+)
+
+// NewApiClient creates a client for the Splunk Observability Cloud API authenticated with the configured access token.
+func NewApiClient() *resty.Client {
+	return apiHealth.trackClient(newClient(config.Config.ApiBaseUrl).
+		SetHeader("Authorization", "Bearer "+config.Config.AccessToken))
+}
+
+// NewIngestClient creates a client for the Splunk Observability Cloud ingest API authenticated with the configured access token.
+// The client doesn't retry failed requests, as the event dispatcher retries the delivery of failed batches itself.
+func NewIngestClient() *resty.Client {
+	return ingestHealth.trackClient(newClient(config.Config.IngestBaseUrl).
+		SetHeader("X-SF-Token", config.Config.AccessToken).
+		SetRetryCount(0))
+}
+
+// NewStreamClient creates a client for the SignalFlow API of Splunk Observability Cloud authenticated with the configured
+// access token. Unless configured otherwise, the stream url is derived from the api url of the same realm. The client
+// has no request timeout, as computations stream their output until they are stopped. It doesn't retry either, as the
+// SignalFlow client reconnects on its own and resty doesn't close the unparsed bodies of retried streaming responses.
+func NewStreamClient() *resty.Client {
+	return newClient(streamBaseUrl(config.Config.StreamBaseUrl, config.Config.ApiBaseUrl)).
+		SetHeader("X-SF-Token", config.Config.AccessToken).
+		SetTimeout(0).
+		SetRetryCount(0)
+}
+
+// streamBaseUrl derives the stream url from the api or app url, e.g. https://stream.us1.signalfx.com for
+// https://api.us1.signalfx.com, unless it is configured explicitly.
+func streamBaseUrl(configured string, apiBaseUrl string) string {
+	if configured != "" {
+		return configured
+	}
+	for _, host := range []string{"://api.", "://app."} {
+		if strings.Contains(apiBaseUrl, host) {
+			return strings.Replace(apiBaseUrl, host, "://stream.", 1)
+		}
+	}
+	return apiBaseUrl
+}
+
+// NewHecClient creates a client for the HTTP Event Collector of Splunk Enterprise or Splunk Cloud Platform
+// authenticated with the configured HEC token. Like the ingest client, it leaves retries to the event dispatcher.
+func NewHecClient() *resty.Client {
+	return newClient(config.Config.HecBaseUrl).
+		SetHeader("Authorization", "Splunk "+config.Config.HecToken).
+		SetRetryCount(0)
+}
+
+func newClient(baseUrl string) *resty.Client {
+	return resty.New().
+		SetBaseURL(strings.TrimRight(baseUrl, "/")).
+		SetHeader(contentType, applicationJsonType).
+		SetTimeout(config.Config.ApiRequestTimeout).
+		SetRetryCount(config.Config.ApiRetryCount).
+		SetRetryWaitTime(config.Config.ApiRetryWaitTime).
+		SetRetryMaxWaitTime(config.Config.ApiRetryMaxWaitTime).
+		AddRetryCondition(shouldRetry).
+		SetRetryAfter(retryAfter).
+		AddRetryHook(func(res *resty.Response, err error) {
+			if err != nil {
+				log.Debug().Err(err).Msg("Retrying Splunk request after error.")
+			} else {
+				log.Debug().Msgf("Retrying Splunk request %s %s after status code %d.", res.Request.Method, res.Request.URL, res.StatusCode())
+			}
+		})
+}
+
+// shouldRetry retries rate limited requests, as well as connection errors and server errors of idempotent requests.
+// A POST may have been processed despite the error, so retrying it could create the same resource twice.
+func shouldRetry(res *resty.Response, err error) bool {
+	if res != nil && res.StatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+	if res != nil && res.Request != nil && !isIdempotent(res.Request.Method) {
+		return false
+	}
+	return err != nil || res.StatusCode() >= http.StatusInternalServerError
+}
+
+func isIdempotent(method string) bool {
+	return method != http.MethodPost && method != http.MethodPatch
+}
+
+// retryAfter honours the Retry-After header of rate limited requests. Returning 0 falls back to exponential backoff.
+func retryAfter(_ *resty.Client, res *resty.Response) (time.Duration, error) {
+	if res == nil || res.StatusCode() != http.StatusTooManyRequests {
+		return 0, nil
+	}
+	return parseRetryAfter(res.Header().Get("Retry-After"), time.Now()), nil
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+// client_test.go
+package extclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steadybit/extension-splunk/config"
+)
+
+// withConfig applies the given client configuration for the duration of a test.
+func withConfig(t *testing.T, baseUrl string, retryCount int) {
+	original := config.Config
+	config.Config.ApiBaseUrl = baseUrl
+	config.Config.IngestBaseUrl = baseUrl
+	config.Config.AccessToken = "token"
+	config.Config.ApiRequestTimeout = 5 * time.Second
+	config.Config.ApiRetryCount = retryCount
+	config.Config.ApiRetryWaitTime = time.Millisecond
+	config.Config.ApiRetryMaxWaitTime = 2 * time.Second
+	config.Config.HealthFailureThreshold = 3
+	t.Cleanup(func() {
+		config.Config = original
+	})
+}
+
+func TestApiClient_RetriesServerErrors(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	withConfig(t, ts.URL+"/", 3)
+
+	res, err := NewApiClient().R().SetContext(context.Background()).Get("/v2/detector")
+	if err != nil {
+		t.Fatalf("request returned error: %v", err)
+	}
+	if res.StatusCode() != http.StatusOK {
+		t.Errorf("Expected status 200 after retries, got %d", res.StatusCode())
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestApiClient_DoesNotRetryClientErrors(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	withConfig(t, ts.URL, 3)
+
+	res, err := NewApiClient().R().Get("/v2/detector")
+	if err != nil {
+		t.Fatalf("request returned error: %v", err)
+	}
+	if res.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", res.StatusCode())
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+}
+
+func TestApiClient_HonoursRetryAfter(t *testing.T) {
+	var requests []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	withConfig(t, ts.URL, 1)
+
+	res, err := NewApiClient().R().Get("/v2/detector")
+	if err != nil {
+		t.Fatalf("request returned error: %v", err)
+	}
+	if res.StatusCode() != http.StatusOK {
+		t.Errorf("Expected status 200 after retry, got %d", res.StatusCode())
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	if waited := requests[1].Sub(requests[0]); waited < time.Second {
+		t.Errorf("Expected the retry to wait for the Retry-After duration, waited %s", waited)
+	}
+}
+
+func TestApiClient_RetriesPostsOnlyIfRateLimited(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+	withConfig(t, ts.URL, 3)
+
+	res, err := NewApiClient().R().SetBody("{}").Post("/v2/slo/search")
+	if err != nil {
+		t.Fatalf("request returned error: %v", err)
+	}
+	if res.StatusCode() != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", res.StatusCode())
+	}
+	if requests != 2 {
+		t.Errorf("Expected the rate limited request to be retried once, got %d requests", requests)
+	}
+}
+
+func TestIngestClient_LeavesRetriesToTheDispatcher(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-SF-Token") != "token" {
+			t.Errorf("unexpected X-SF-Token header: %s", r.Header.Get("X-SF-Token"))
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	withConfig(t, ts.URL, 3)
+
+	res, err := NewIngestClient().R().SetBody("[]").Post("/v2/event")
+	if err != nil {
+		t.Fatalf("request returned error: %v", err)
+	}
+	if res.StatusCode() != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", res.StatusCode())
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "seconds", value: "7", want: 7 * time.Second},
+		{name: "negative seconds", value: "-1", want: 0},
+		{name: "http date", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{name: "http date in the past", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "invalid", value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s; want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamBaseUrl(t *testing.T) {
+	tests := []struct {
+		configured string
+		apiBaseUrl string
+		want       string
+	}{
+		{apiBaseUrl: "https://api.us1.signalfx.com/", want: "https://stream.us1.signalfx.com/"},
+		{apiBaseUrl: "https://app.eu0.signalfx.com", want: "https://stream.eu0.signalfx.com"},
+		{configured: "http://localhost:8080", apiBaseUrl: "https://api.us1.signalfx.com", want: "http://localhost:8080"},
+		{apiBaseUrl: "http://localhost:9090", want: "http://localhost:9090"},
+	}
+	for _, tt := range tests {
+		if got := streamBaseUrl(tt.configured, tt.apiBaseUrl); got != tt.want {
+			t.Errorf("streamBaseUrl(%q, %q) = %q, want %q", tt.configured, tt.apiBaseUrl, got, tt.want)
+		}
+	}
+}
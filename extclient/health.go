@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extclient
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-kit/exthealth"
+	"github.com/steadybit/extension-kit/extsignals"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+// Health describes the outcome of the recent calls to one of the Splunk endpoints.
+type Health struct {
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure         *time.Time `json:"lastFailure,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+}
+
+// HealthReport is served by the /health/splunk endpoint.
+type HealthReport struct {
+	Ready  bool   `json:"ready"`
+	Api    Health `json:"api"`
+	Ingest Health `json:"ingest"`
+}
+
+type healthTracker struct {
+	mu     sync.Mutex
+	health Health
+}
+
+var (
+	apiHealth    = newHealthTracker()
+	ingestHealth = newHealthTracker()
+	// readinessMu serializes the readiness updates, so a stale health can't overwrite a newer one in the readiness probe.
+	readinessMu sync.Mutex
+	// readinessActive is set once the extension is started and cleared on shutdown, so that the tracked health only
+	// drives the readiness probe in between.
+	readinessActive bool
+	ready           bool
+)
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{health: Health{Healthy: true}}
+}
+
+func (t *healthTracker) trackClient(client *resty.Client) *resty.Client {
+	return client.
+		OnSuccess(func(_ *resty.Client, res *resty.Response) {
+			if isHealthyStatus(res.StatusCode()) {
+				t.recordSuccess()
+			} else {
+				t.recordFailure(fmt.Sprintf("%s %s responded with status code %d", res.Request.Method, res.Request.URL, res.StatusCode()))
+			}
+		}).
+		OnError(func(req *resty.Request, err error) {
+			t.recordFailure(fmt.Sprintf("%s %s failed: %s", req.Method, req.URL, err.Error()))
+		})
+}
+
+// isHealthyStatus treats every response proving that Splunk is reachable and accepts the access token as healthy.
+func isHealthyStatus(statusCode int) bool {
+	return statusCode != http.StatusUnauthorized &&
+		statusCode != http.StatusForbidden &&
+		statusCode != http.StatusTooManyRequests &&
+		statusCode < http.StatusInternalServerError
+}
+
+func (t *healthTracker) recordSuccess() {
+	t.mu.Lock()
+	now := time.Now()
+	t.health.Healthy = true
+	t.health.ConsecutiveFailures = 0
+	t.health.LastSuccess = &now
+	t.mu.Unlock()
+	updateReadiness()
+}
+
+func (t *healthTracker) recordFailure(message string) {
+	t.mu.Lock()
+	now := time.Now()
+	t.health.ConsecutiveFailures++
+	t.health.LastFailure = &now
+	t.health.LastError = message
+	t.health.Healthy = t.health.ConsecutiveFailures < max(config.Config.HealthFailureThreshold, 1)
+	t.mu.Unlock()
+	updateReadiness()
+}
+
+func (t *healthTracker) get() Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.health
+}
+
+// GetHealthReport returns the health of the Splunk API and ingest endpoints.
+func GetHealthReport() HealthReport {
+	return HealthReport{
+		Ready:  apiHealth.get().Healthy,
+		Api:    apiHealth.get(),
+		Ingest: ingestHealth.get(),
+	}
+}
+
+// ActivateReadiness replaces the unconditional readiness of the extension with the health of the Splunk API. Without
+// a working API, the extension can neither discover targets nor evaluate checks.
+func ActivateReadiness() {
+	extsignals.AddSignalHandler(extsignals.SignalHandler{
+		Handler: func(_ os.Signal) {
+			readinessMu.Lock()
+			defer readinessMu.Unlock()
+			readinessActive = false
+		},
+		Order: extsignals.OrderReadinessFalse,
+		Name:  "DeactivateSplunkReadiness",
+	})
+	readinessMu.Lock()
+	defer readinessMu.Unlock()
+	readinessActive = true
+	ready = apiHealth.get().Healthy
+	exthealth.SetReady(ready)
+}
+
+func updateReadiness() {
+	readinessMu.Lock()
+	defer readinessMu.Unlock()
+	if !readinessActive {
+		return
+	}
+	health := apiHealth.get()
+	if ready != health.Healthy {
+		ready = health.Healthy
+		if !health.Healthy {
+			log.Warn().Msgf("Splunk API is not healthy, marking the extension as not ready: %s", health.LastError)
+		}
+		exthealth.SetReady(health.Healthy)
+	}
+}
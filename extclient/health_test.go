@@ -0,0 +1,59 @@
+// health_test.go
+package extclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealth_TracksConsecutiveFailures(t *testing.T) {
+	status := http.StatusUnauthorized
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+	withConfig(t, ts.URL, 0)
+	apiHealth = newHealthTracker()
+	ingestHealth = newHealthTracker()
+
+	client := NewApiClient()
+	for i := 0; i < 2; i++ {
+		_, _ = client.R().Get("/v2/detector")
+	}
+	if report := GetHealthReport(); !report.Ready || report.Api.ConsecutiveFailures != 2 {
+		t.Errorf("Expected the API to stay healthy below the threshold, got %+v", report.Api)
+	}
+
+	_, _ = client.R().Get("/v2/detector")
+	report := GetHealthReport()
+	if report.Ready || report.Api.Healthy {
+		t.Errorf("Expected the API to be unhealthy after reaching the threshold, got %+v", report.Api)
+	}
+	if report.Api.LastError == "" || report.Api.LastFailure == nil {
+		t.Errorf("Expected the last failure to be recorded, got %+v", report.Api)
+	}
+	if !report.Ingest.Healthy {
+		t.Errorf("Expected the ingest health to be unaffected, got %+v", report.Ingest)
+	}
+
+	// A 404 proves that Splunk is reachable and accepts the token.
+	status = http.StatusNotFound
+	_, _ = client.R().Get("/v2/detector")
+	report = GetHealthReport()
+	if !report.Ready || report.Api.ConsecutiveFailures != 0 || report.Api.LastSuccess == nil {
+		t.Errorf("Expected the API to recover after a successful call, got %+v", report.Api)
+	}
+}
+
+func TestHealth_TracksConnectionErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+	withConfig(t, ts.URL, 0)
+	ingestHealth = newHealthTracker()
+
+	_, _ = NewIngestClient().R().SetBody("[]").Post("/v2/event")
+	if health := GetHealthReport().Ingest; health.ConsecutiveFailures != 1 || health.LastError == "" {
+		t.Errorf("Expected the connection error to be recorded, got %+v", health)
+	}
+}
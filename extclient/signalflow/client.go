@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+// Package signalflow starts SignalFlow computations via the streaming HTTP API of Splunk Observability Cloud and
+// decodes the server-sent events of their output.
+package signalflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultMaxReconnects = 3
+	defaultReconnectWait = time.Second
+	stopTimeout          = 5 * time.Second
+)
+
+// Client starts SignalFlow computations. The resty client must be configured with the stream url and access token
+// of the realm and must not set a request timeout, as computations are bound by their context instead.
+type Client struct {
+	client        *resty.Client
+	maxReconnects int
+	reconnectWait time.Duration
+}
+
+// Options configure the time range and resolution of a computation.
+type Options struct {
+	// Start of the computation, defaults to now.
+	Start time.Time
+	// Stop of the computation. The computation keeps streaming new values if not set.
+	Stop time.Time
+	// Resolution of the computation. Splunk chooses the resolution based on the data if not set.
+	Resolution time.Duration
+	// Immediate computes the time range as fast as possible rather than waiting for late data.
+	Immediate bool
+}
+
+// ApiError is returned if the SignalFlow API responded with an unexpected status code.
+type ApiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("SignalFlow API responded with status code %d: %s", e.StatusCode, e.Body)
+}
+
+func NewClient(client *resty.Client) *Client {
+	return &Client{client: client, maxReconnects: defaultMaxReconnects, reconnectWait: defaultReconnectWait}
+}
+
+// WithReconnects configures how often a broken stream is resumed and how long to wait before each attempt.
+func (c *Client) WithReconnects(maxReconnects int, wait time.Duration) *Client {
+	c.maxReconnects = maxReconnects
+	c.reconnectWait = wait
+	return c
+}
+
+// Execute starts the program. It returns once the stream is established, the output is consumed through the
+// computation's data channel. Failed connection attempts are retried like broken streams, the resty client is expected
+// not to retry on its own.
+func (c *Client) Execute(ctx context.Context, program string, options Options) (*Computation, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	body, err := c.connect(ctx, program, options)
+	for attempt := 1; err != nil && isRecoverable(err) && ctx.Err() == nil && attempt <= c.maxReconnects; attempt++ {
+		log.Debug().Err(err).Msgf("Retrying SignalFlow execution (attempt %d of %d).", attempt, c.maxReconnects)
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.reconnectWait):
+			body, err = c.connect(ctx, program, options)
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	computation := newComputation(ctx, cancel, c)
+	go computation.run(body, program, options)
+	return computation, nil
+}
+
+func (c *Client) connect(ctx context.Context, program string, options Options) (io.ReadCloser, error) {
+	req := c.client.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeader("Content-Type", "text/plain").
+		SetHeader("Accept", "text/event-stream").
+		SetBody(program)
+	if !options.Start.IsZero() {
+		req.SetQueryParam("start", strconv.FormatInt(options.Start.UnixMilli(), 10))
+	}
+	if !options.Stop.IsZero() {
+		req.SetQueryParam("stop", strconv.FormatInt(options.Stop.UnixMilli(), 10))
+	}
+	if options.Resolution > 0 {
+		req.SetQueryParam("resolution", strconv.FormatInt(options.Resolution.Milliseconds(), 10))
+	}
+	if options.Immediate {
+		req.SetQueryParam("immediate", "true")
+	}
+
+	res, err := req.Post("/v2/signalflow/execute")
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SignalFlow program: %w", err)
+	}
+	if !res.IsSuccess() {
+		defer res.RawBody().Close()
+		body, _ := io.ReadAll(io.LimitReader(res.RawBody(), 64*1024))
+		return nil, &ApiError{StatusCode: res.StatusCode(), Body: strings.TrimSpace(string(body))}
+	}
+	return res.RawBody(), nil
+}
+
+// stop asks Splunk to stop the computation, so it doesn't keep running until it notices the closed connection.
+func (c *Client) stop(handle string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	res, err := c.client.R().
+		SetContext(ctx).
+		Post("/v2/signalflow/" + handle + "/stop")
+	if err != nil {
+		return fmt.Errorf("failed to stop SignalFlow computation %s: %w", handle, err)
+	}
+	// The computation may have ended by itself in the meantime.
+	if !res.IsSuccess() && res.StatusCode() != http.StatusNotFound {
+		return &ApiError{StatusCode: res.StatusCode(), Body: strings.TrimSpace(res.String())}
+	}
+	return nil
+}
+
+// isRecoverable tells whether a broken stream may be resumed. Program errors and rejected requests are permanent.
+func isRecoverable(err error) bool {
+	if _, ok := errors.AsType[*ErrorMessage](err); ok {
+		return false
+	}
+	if apiErr, ok := errors.AsType[*ApiError](err); ok {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
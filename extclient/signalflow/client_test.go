@@ -0,0 +1,204 @@
+// client_test.go
+package signalflow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extclient"
+	"github.com/steadybit/extension-splunk/extclient/signalflow/signalflowtest"
+)
+
+func newTestClient(server *signalflowtest.Server) *Client {
+	return NewClient(resty.New().SetBaseURL(server.URL)).WithReconnects(2, time.Millisecond)
+}
+
+func newTestServer() *signalflowtest.Server {
+	return signalflowtest.NewServer().
+		AddSeries("AAA", map[string]any{"sf_metric": "latency", "sf_key": []any{"sf_metric", "service"}, "service": "checkout", "sf_isPreQuantized": true}).
+		AddData(1000, map[string]float64{"AAA": 100}).
+		AddData(2000, map[string]float64{"AAA": 200}).
+		AddData(3000, map[string]float64{"AAA": 300})
+}
+
+func TestExecute_DecodesStream(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	computation, err := newTestClient(server).Execute(context.Background(), "data('latency').publish()", Options{
+		Start:      time.UnixMilli(1000),
+		Stop:       time.UnixMilli(3000),
+		Resolution: 10 * time.Second,
+		Immediate:  true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	batches, err := computation.Collect()
+	if err != nil {
+		t.Fatalf("Collect() returned error: %v", err)
+	}
+
+	if len(batches) != 3 || batches[2].TimestampMs != 3000 || batches[2].Points[0].Value != 300 {
+		t.Fatalf("Unexpected batches %+v", batches)
+	}
+	metadata := batches[0].Points[0].Metadata
+	if metadata.Metric() != "latency" {
+		t.Errorf("Expected metric latency, got %s", metadata.Metric())
+	}
+	if dimensions := metadata.Dimensions(); len(dimensions) != 1 || dimensions["service"] != "checkout" {
+		t.Errorf("Expected the key dimensions, got %v", dimensions)
+	}
+	if computation.Handle() != "handle-1" {
+		t.Errorf("Expected the handle of the job, got %s", computation.Handle())
+	}
+
+	executions := server.Executions()
+	if len(executions) != 1 {
+		t.Fatalf("Expected 1 execution, got %d", len(executions))
+	}
+	if execution := executions[0]; execution.Program != "data('latency').publish()" || execution.Start != 1000 || execution.Stop != 3000 || execution.Resolution != 10000 || !execution.Immediate {
+		t.Errorf("Unexpected execution %+v", execution)
+	}
+}
+
+func TestExecute_ResumesBrokenStreams(t *testing.T) {
+	server := newTestServer().DisconnectAfter(2)
+	defer server.Close()
+
+	computation, err := newTestClient(server).Execute(context.Background(), "data('latency').publish()", Options{Start: time.UnixMilli(1000)})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	batches, err := computation.Collect()
+	if err != nil {
+		t.Fatalf("Collect() returned error: %v", err)
+	}
+
+	if len(batches) != 3 {
+		t.Errorf("Expected every value exactly once, got %+v", batches)
+	}
+	executions := server.Executions()
+	if len(executions) != 2 || executions[1].Start != 2001 {
+		t.Errorf("Expected the computation to resume after the last value, got %+v", executions)
+	}
+}
+
+func TestExecute_ReportsProgramErrors(t *testing.T) {
+	server := newTestServer().FailProgram("unknown function 'dta'")
+	defer server.Close()
+
+	computation, err := newTestClient(server).Execute(context.Background(), "dta('latency').publish()", Options{})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	_, err = computation.Collect()
+	if programErr, ok := errors.AsType[*ErrorMessage](err); !ok || programErr.Message != "unknown function 'dta'" {
+		t.Errorf("Expected the program error, got %v", err)
+	}
+	if len(server.Executions()) != 1 {
+		t.Errorf("Expected program errors not to be retried, got %d executions", len(server.Executions()))
+	}
+}
+
+func TestExecute_RetriesOnlyInTheClient(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.ApiRetryCount = 3
+	config.Config.ApiRetryWaitTime = time.Millisecond
+	config.Config.ApiRetryMaxWaitTime = time.Millisecond
+
+	tests := []struct {
+		name       string
+		rejections int
+		wantErr    bool
+	}{
+		{name: "recovers", rejections: 2},
+		{name: "gives up", rejections: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer().RejectExecutions(http.StatusServiceUnavailable, tt.rejections)
+			defer server.Close()
+			config.Config.StreamBaseUrl = server.URL
+
+			client := NewClient(extclient.NewStreamClient()).WithReconnects(2, time.Millisecond)
+			computation, err := client.Execute(context.Background(), "data('latency').publish()", Options{})
+			if tt.wantErr {
+				if apiErr, ok := errors.AsType[*ApiError](err); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+					t.Errorf("Expected an API error, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Execute() returned error: %v", err)
+			} else if batches, err := computation.Collect(); err != nil || len(batches) != 3 {
+				t.Errorf("Expected all values after reconnecting, got %d batches and error %v", len(batches), err)
+			}
+			if executions := server.Executions(); len(executions) != client.maxReconnects+1 {
+				t.Errorf("Expected %d executions, got %d", client.maxReconnects+1, len(executions))
+			}
+		})
+	}
+}
+
+func TestExecute_ReportsRejectedRequests(t *testing.T) {
+	server := newTestServer().RespondWithStatus(http.StatusUnauthorized)
+	defer server.Close()
+
+	_, err := newTestClient(server).Execute(context.Background(), "data('latency').publish()", Options{})
+	if apiErr, ok := errors.AsType[*ApiError](err); !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected an API error, got %v", err)
+	}
+}
+
+func TestComputation_StopAfterEndOfChannel(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	computation, err := newTestClient(server).Execute(context.Background(), "data('latency').publish()", Options{Stop: time.UnixMilli(3000)})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	if _, err := computation.Collect(); err != nil {
+		t.Fatalf("Collect() returned error: %v", err)
+	}
+	computation.Stop()
+
+	if stopped := server.Stopped(); len(stopped) != 0 {
+		t.Errorf("Expected the ended computation not to be stopped in Splunk, got %v", stopped)
+	}
+}
+
+func TestComputation_Stop(t *testing.T) {
+	server := newTestServer().KeepOpen()
+	defer server.Close()
+
+	computation, err := newTestClient(server).Execute(context.Background(), "data('latency').publish()", Options{})
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	for range 3 {
+		<-computation.Data()
+	}
+	computation.Stop()
+	computation.Stop()
+
+	select {
+	case _, open := <-computation.Data():
+		if open {
+			t.Error("Expected no more data after stopping the computation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the data channel to be closed after stopping the computation")
+	}
+	if stopped := server.Stopped(); len(stopped) != 1 || stopped[0] != "handle-1" {
+		t.Errorf("Expected the computation to be stopped in Splunk, got %v", stopped)
+	}
+	if computation.Err() != nil {
+		t.Errorf("Expected no error for a stopped computation, got %v", computation.Err())
+	}
+}
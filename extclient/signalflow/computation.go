@@ -0,0 +1,293 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package signalflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errStreamEnded is returned by the decoder if the stream ended without an END_OF_CHANNEL message.
+var errStreamEnded = errors.New("SignalFlow stream ended unexpectedly")
+
+// Computation is a running SignalFlow program. Its output is delivered through the data channel, which is closed
+// once the computation ended, failed or was stopped.
+type Computation struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	client *Client
+	data   chan Batch
+
+	mu            sync.Mutex
+	handle        string
+	ended         bool
+	metadata      map[string]Metadata
+	lastTimestamp int64
+	err           error
+	stopOnce      sync.Once
+}
+
+func newComputation(ctx context.Context, cancel context.CancelFunc, client *Client) *Computation {
+	return &Computation{
+		ctx:      ctx,
+		cancel:   cancel,
+		client:   client,
+		data:     make(chan Batch, 16),
+		metadata: make(map[string]Metadata),
+	}
+}
+
+// Data returns the channel the values are delivered through.
+func (c *Computation) Data() <-chan Batch {
+	return c.data
+}
+
+// Err returns the error the computation failed with, once the data channel is closed.
+func (c *Computation) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Handle returns the id Splunk assigned to the computation.
+func (c *Computation) Handle() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.handle
+}
+
+// Metadata returns the metadata of the time series with the given TSID.
+func (c *Computation) Metadata(tsId string) (Metadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	metadata, ok := c.metadata[tsId]
+	return metadata, ok
+}
+
+// Collect consumes the whole output of the computation. It is meant for computations with a stop time.
+func (c *Computation) Collect() ([]Batch, error) {
+	var batches []Batch
+	for batch := range c.data {
+		batches = append(batches, batch)
+	}
+	return batches, c.Err()
+}
+
+// Stop ends the computation. It is safe to call Stop multiple times and after the computation ended. Splunk is only
+// asked to stop the job if it is still running.
+func (c *Computation) Stop() {
+	c.stopOnce.Do(func() {
+		c.cancel()
+		if handle, running := c.runningHandle(); running {
+			if err := c.client.stop(handle); err != nil {
+				log.Debug().Err(err).Msgf("Failed to stop SignalFlow computation %s.", handle)
+			}
+		}
+	})
+}
+
+// runningHandle returns the handle of the job, unless the job ended or hasn't been started yet.
+func (c *Computation) runningHandle() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.handle, c.handle != "" && !c.ended
+}
+
+func (c *Computation) run(body io.ReadCloser, program string, options Options) {
+	defer close(c.data)
+	defer c.cancel()
+
+	reconnects := 0
+	for {
+		received, err := c.consume(body)
+		_ = body.Close()
+		if err == nil || c.ctx.Err() != nil {
+			return
+		}
+		if received {
+			reconnects = 0
+		}
+		if !isRecoverable(err) || reconnects >= c.client.maxReconnects {
+			c.fail(err)
+			return
+		}
+
+		// Resume after the last value received, so no values are delivered twice.
+		resume := options
+		if lastTimestamp := c.lastTimestampMs(); lastTimestamp > 0 {
+			resume.Start = time.UnixMilli(lastTimestamp + 1)
+		}
+		if !resume.Stop.IsZero() && !resume.Start.IsZero() && resume.Start.After(resume.Stop) {
+			return
+		}
+
+		for {
+			reconnects++
+			log.Debug().Err(err).Msgf("Reconnecting SignalFlow computation (attempt %d of %d).", reconnects, c.client.maxReconnects)
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(c.client.reconnectWait):
+			}
+			body, err = c.client.connect(c.ctx, program, resume)
+			if err == nil {
+				break
+			}
+			if c.ctx.Err() != nil {
+				return
+			}
+			if !isRecoverable(err) || reconnects >= c.client.maxReconnects {
+				c.fail(err)
+				return
+			}
+		}
+	}
+}
+
+// consume decodes the server-sent events of one connection. It returns nil once the computation ended and reports
+// whether any data was received.
+func (c *Computation) consume(body io.Reader) (bool, error) {
+	received := false
+	reader := bufio.NewReader(body)
+	var event string
+	var data bytes.Buffer
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+			if errors.Is(err, io.EOF) {
+				return received, errStreamEnded
+			}
+			return received, fmt.Errorf("failed to read SignalFlow stream: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if event == "" && data.Len() == 0 {
+				continue
+			}
+			ended, isData, handleErr := c.handleEvent(event, data.Bytes())
+			received = received || isData
+			event = ""
+			data.Reset()
+			if handleErr != nil || ended {
+				return received, handleErr
+			}
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+// handleEvent processes one server-sent event and reports whether the computation ended and whether it carried data.
+func (c *Computation) handleEvent(event string, payload []byte) (bool, bool, error) {
+	switch event {
+	case "metadata":
+		var metadata Metadata
+		if err := json.Unmarshal(payload, &metadata); err != nil {
+			return false, false, fmt.Errorf("failed to decode SignalFlow metadata: %w", err)
+		}
+		c.mu.Lock()
+		c.metadata[metadata.TsId] = metadata
+		c.mu.Unlock()
+
+	case "data":
+		var message dataMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return false, false, fmt.Errorf("failed to decode SignalFlow data: %w", err)
+		}
+		batch := Batch{TimestampMs: message.LogicalTimestampMs, Points: make([]Point, 0, len(message.Data))}
+		c.mu.Lock()
+		for _, value := range message.Data {
+			metadata, ok := c.metadata[value.TsId]
+			if !ok {
+				metadata = Metadata{TsId: value.TsId}
+			}
+			batch.Points = append(batch.Points, Point{TsId: value.TsId, Value: value.Value, Metadata: metadata})
+		}
+		c.lastTimestamp = max(c.lastTimestamp, message.LogicalTimestampMs)
+		c.mu.Unlock()
+		select {
+		case c.data <- batch:
+		case <-c.ctx.Done():
+			return true, true, nil
+		}
+		return false, true, nil
+
+	case "control-message":
+		var message controlMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return false, false, fmt.Errorf("failed to decode SignalFlow control message: %w", err)
+		}
+		switch message.Event {
+		case ControlJobStart:
+			c.mu.Lock()
+			c.handle = message.Handle
+			c.ended = false
+			c.mu.Unlock()
+		case ControlEndOfChannel:
+			c.markEnded()
+			return true, false, nil
+		case ControlChannelAbort:
+			c.markEnded()
+			reason := "unknown reason"
+			if message.AbortInfo != nil && message.AbortInfo.SfJobAbortReason != "" {
+				reason = message.AbortInfo.SfJobAbortReason
+			}
+			return false, false, &ErrorMessage{ErrorType: ControlChannelAbort, Message: "the computation was aborted: " + reason}
+		}
+
+	case "message":
+		var message infoMessage
+		if err := json.Unmarshal(payload, &message); err == nil {
+			log.Debug().Msgf("SignalFlow computation %s: %v", c.Handle(), message.Message)
+		}
+
+	case "error":
+		var message ErrorMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return false, false, fmt.Errorf("failed to decode SignalFlow error: %w", err)
+		}
+		return false, false, &message
+	}
+	return false, false, nil
+}
+
+func (c *Computation) lastTimestampMs() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastTimestamp
+}
+
+// markEnded records that Splunk ended the job, so it doesn't need to be stopped anymore.
+func (c *Computation) markEnded() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ended = true
+}
+
+func (c *Computation) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package signalflow
+
+import (
+	"strings"
+)
+
+const (
+	ControlStreamStart  = "STREAM_START"
+	ControlJobStart     = "JOB_START"
+	ControlJobProgress  = "JOB_PROGRESS"
+	ControlChannelAbort = "CHANNEL_ABORT"
+	ControlEndOfChannel = "END_OF_CHANNEL"
+)
+
+// Metadata describes a time series of the computation. The properties contain the metric name, the dimensions and
+// internal properties prefixed with "sf_".
+type Metadata struct {
+	TsId       string         `json:"tsId"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Metric returns the name of the metric the time series was computed from.
+func (m Metadata) Metric() string {
+	if metric, ok := m.Properties["sf_originatingMetric"].(string); ok && metric != "" {
+		return metric
+	}
+	metric, _ := m.Properties["sf_metric"].(string)
+	return metric
+}
+
+// Dimensions returns the dimensions of the time series, without the internal properties.
+func (m Metadata) Dimensions() map[string]string {
+	dimensions := make(map[string]string)
+	for _, key := range m.Key() {
+		if value, ok := m.Properties[key].(string); ok {
+			dimensions[key] = value
+		}
+	}
+	if len(dimensions) > 0 {
+		return dimensions
+	}
+	for key, value := range m.Properties {
+		if text, ok := value.(string); ok && !strings.HasPrefix(key, "sf_") {
+			dimensions[key] = text
+		}
+	}
+	return dimensions
+}
+
+// Key returns the names of the dimensions identifying the time series, e.g. the grouping of an aggregation.
+func (m Metadata) Key() []string {
+	values, _ := m.Properties["sf_key"].([]any)
+	key := make([]string, 0, len(values))
+	for _, value := range values {
+		if name, ok := value.(string); ok && !strings.HasPrefix(name, "sf_") {
+			key = append(key, name)
+		}
+	}
+	return key
+}
+
+// dataMessage holds the values of all time series at one logical timestamp.
+type dataMessage struct {
+	LogicalTimestampMs int64 `json:"logicalTimestampMs"`
+	Data               []struct {
+		TsId  string  `json:"tsId"`
+		Value float64 `json:"value"`
+	} `json:"data"`
+}
+
+type controlMessage struct {
+	Event       string `json:"event"`
+	Handle      string `json:"handle"`
+	TimestampMs int64  `json:"timestampMs"`
+	AbortInfo   *struct {
+		SfJobAbortReason string `json:"sf_job_abortReason"`
+	} `json:"abortInfo"`
+}
+
+type infoMessage struct {
+	LogicalTimestampMs int64          `json:"logicalTimestampMs"`
+	Message            map[string]any `json:"message"`
+}
+
+// ErrorMessage is sent if the program can't be executed, e.g. because of a syntax error.
+type ErrorMessage struct {
+	Code      int    `json:"error"`
+	ErrorType string `json:"errorType"`
+	Message   string `json:"message"`
+}
+
+func (e *ErrorMessage) Error() string {
+	return "SignalFlow program failed: " + e.Message + " (" + e.ErrorType + ")"
+}
+
+// Point is the value of one time series.
+type Point struct {
+	TsId     string
+	Value    float64
+	Metadata Metadata
+}
+
+// Batch holds the values of all time series at one logical timestamp.
+type Batch struct {
+	TimestampMs int64
+	Points      []Point
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+// Package signalflowtest provides a local fake of the SignalFlow streaming API for unit tests.
+package signalflowtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Execution is a recorded request to execute a program.
+type Execution struct {
+	Program    string
+	Start      int64
+	Stop       int64
+	Resolution int64
+	Immediate  bool
+}
+
+type series struct {
+	TsId       string         `json:"tsId"`
+	Properties map[string]any `json:"properties"`
+}
+
+type batch struct {
+	timestamp int64
+	values    map[string]float64
+}
+
+// Server streams the configured time series to every execution. Only values within the requested start and stop
+// times are sent, so resumed computations continue where they left off.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	series     []series
+	batches    []batch
+	status     int
+	rejections int
+	programErr string
+	disconnect int
+	keepOpen   bool
+	executions []Execution
+	stopped    []string
+	stopCh     chan struct{}
+}
+
+// NewServer starts a fake SignalFlow server. Close it once the test finished.
+func NewServer() *Server {
+	s := &Server{stopCh: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/signalflow/execute", s.execute)
+	mux.HandleFunc("POST /v2/signalflow/{handle}/stop", s.stop)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddSeries declares a time series. The properties contain the metric name and dimensions, e.g.
+// {"sf_metric": "latency", "sf_key": []any{"sf_metric", "service"}, "service": "checkout"}.
+func (s *Server) AddSeries(tsId string, properties map[string]any) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series = append(s.series, series{TsId: tsId, Properties: properties})
+	return s
+}
+
+// AddData adds the values of the time series at the logical timestamp.
+func (s *Server) AddData(timestampMs int64, values map[string]float64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, batch{timestamp: timestampMs, values: values})
+	return s
+}
+
+// RespondWithStatus rejects all executions with the given status code.
+func (s *Server) RespondWithStatus(status int) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	return s
+}
+
+// RejectExecutions rejects the next executions with the given status code, later executions succeed.
+func (s *Server) RejectExecutions(status int, executions int) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.rejections = executions
+	return s
+}
+
+// FailProgram responds with an error message, as sent for invalid programs.
+func (s *Server) FailProgram(message string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.programErr = message
+	return s
+}
+
+// DisconnectAfter breaks the connection of the next execution after the given number of data messages.
+func (s *Server) DisconnectAfter(messages int) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnect = messages
+	return s
+}
+
+// KeepOpen keeps the streams open after all values were sent, like a computation without stop time, until the
+// computation is stopped or the client disconnects.
+func (s *Server) KeepOpen() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keepOpen = true
+	return s
+}
+
+// Executions returns the recorded executions.
+func (s *Server) Executions() []Execution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Execution(nil), s.executions...)
+}
+
+// Stopped returns the handles of the stopped computations.
+func (s *Server) Stopped() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.stopped...)
+}
+
+func (s *Server) execute(w http.ResponseWriter, r *http.Request) {
+	program, _ := io.ReadAll(r.Body)
+	query := r.URL.Query()
+	execution := Execution{
+		Program:    string(program),
+		Start:      parseInt(query.Get("start")),
+		Stop:       parseInt(query.Get("stop")),
+		Resolution: parseInt(query.Get("resolution")),
+		Immediate:  query.Get("immediate") == "true",
+	}
+
+	s.mu.Lock()
+	s.executions = append(s.executions, execution)
+	handle := fmt.Sprintf("handle-%d", len(s.executions))
+	status, programErr, keepOpen := s.status, s.programErr, s.keepOpen
+	if s.rejections > 0 {
+		s.rejections--
+		if s.rejections == 0 {
+			s.status = 0
+		}
+	}
+	disconnect := s.disconnect
+	s.disconnect = 0
+	seriesList := append([]series(nil), s.series...)
+	batches := append([]batch(nil), s.batches...)
+	s.mu.Unlock()
+
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher, _ := w.(http.Flusher)
+	send := func(event string, payload any) {
+		body, _ := json.MarshalIndent(payload, "", "  ")
+		_, _ = fmt.Fprintf(w, "event: %s\n", event)
+		// Multi-line data fields are sent like the real API does.
+		for _, line := range strings.Split(string(body), "\n") {
+			_, _ = fmt.Fprintf(w, "data: %s\n", line)
+		}
+		_, _ = fmt.Fprint(w, "\n")
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	send("control-message", map[string]any{"event": "STREAM_START"})
+	if programErr != "" {
+		send("error", map[string]any{"error": 400, "errorType": "ANALYTICS_PROGRAM_NAME_ERROR", "message": programErr})
+		return
+	}
+	send("control-message", map[string]any{"event": "JOB_START", "handle": handle})
+	for _, series := range seriesList {
+		send("metadata", series)
+	}
+
+	sent := 0
+	for _, batch := range batches {
+		if execution.Start > 0 && batch.timestamp < execution.Start || execution.Stop > 0 && batch.timestamp > execution.Stop {
+			continue
+		}
+		if disconnect > 0 && sent == disconnect {
+			// Ending the response without END_OF_CHANNEL looks like a broken connection to the client.
+			return
+		}
+		data := make([]map[string]any, 0, len(batch.values))
+		for tsId, value := range batch.values {
+			data = append(data, map[string]any{"tsId": tsId, "value": value})
+		}
+		send("data", map[string]any{"logicalTimestampMs": batch.timestamp, "data": data})
+		sent++
+	}
+
+	if keepOpen {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.stopCh:
+		}
+	}
+	send("control-message", map[string]any{"event": "END_OF_CHANNEL"})
+}
+
+func (s *Server) stop(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.stopped = append(s.stopped, r.PathValue("handle"))
+	select {
+	case <-s.stopCh:
+	default:
+		close(s.stopCh)
+	}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseInt(value string) int64 {
+	parsed, _ := strconv.ParseInt(value, 10, 64)
+	return parsed
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// ValidateConnectivity calls a cheap read endpoint of the API and dry-runs the ingest endpoint to verify that both are
+// reachable and that the access token has the required "API" and "Ingest" permissions. The ingest endpoint is skipped
+// if ingestClient is nil, as the "Ingest" permission is only required if events, traces or metrics are sent to it.
+func ValidateConnectivity(ctx context.Context, apiClient *resty.Client, ingestClient *resty.Client) []error {
+	var errs []error
+
+	res, err := apiClient.R().
+		SetContext(ctx).
+		SetQueryParam("limit", "1").
+		Get("/v2/detector")
+	if err := checkResponse("API", "API", res, err); err != nil {
+		errs = append(errs, err)
+	} else {
+		log.Info().Msg("Validated access to the Splunk Observability Cloud API.")
+	}
+
+	if ingestClient == nil {
+		return errs
+	}
+
+	// Posting an empty list of events is accepted by Splunk without creating any event.
+	res, err = ingestClient.R().
+		SetContext(ctx).
+		SetBody("[]").
+		Post("/v2/event")
+	if err := checkResponse("ingest API", "Ingest", res, err); err != nil {
+		errs = append(errs, err)
+	} else {
+		log.Info().Msg("Validated access to the Splunk Observability Cloud ingest API.")
+	}
+
+	return errs
+}
+
+func checkResponse(endpoint string, permission string, res *resty.Response, err error) error {
+	if err != nil {
+		return fmt.Errorf("the Splunk Observability Cloud %s is not reachable: %w", endpoint, err)
+	}
+	switch {
+	case res.StatusCode() == http.StatusUnauthorized:
+		return fmt.Errorf("the Splunk Observability Cloud %s rejected the access token (HTTP %d), check that the token is valid and has the '%s' permission", endpoint, res.StatusCode(), permission)
+	case res.StatusCode() == http.StatusForbidden:
+		return fmt.Errorf("the access token is missing the '%s' permission required by the Splunk Observability Cloud %s (HTTP %d)", permission, endpoint, res.StatusCode())
+	case !res.IsSuccess():
+		return fmt.Errorf("the Splunk Observability Cloud %s responded with unexpected status code %d: %s", endpoint, res.StatusCode(), res.String())
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// validation_test.go
+package extclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newValidationServer(apiStatus int, ingestStatus int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/detector":
+			w.WriteHeader(apiStatus)
+		case "/v2/event":
+			w.WriteHeader(ingestStatus)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestValidateConnectivity_Success(t *testing.T) {
+	ts := newValidationServer(http.StatusOK, http.StatusOK)
+	defer ts.Close()
+	withConfig(t, ts.URL, 0)
+
+	errs := ValidateConnectivity(context.Background(), NewApiClient(), NewIngestClient())
+	if len(errs) != 0 {
+		t.Errorf("Expected no validation errors, got %v", errs)
+	}
+}
+
+func TestValidateConnectivity_MissingPermissions(t *testing.T) {
+	ts := newValidationServer(http.StatusForbidden, http.StatusUnauthorized)
+	defer ts.Close()
+	withConfig(t, ts.URL, 0)
+
+	errs := ValidateConnectivity(context.Background(), NewApiClient(), NewIngestClient())
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 validation errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "'API' permission") {
+		t.Errorf("Expected the API permission to be reported, got: %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), "'Ingest' permission") {
+		t.Errorf("Expected the Ingest permission to be reported, got: %v", errs[1])
+	}
+}
+
+func TestValidateConnectivity_Unreachable(t *testing.T) {
+	ts := newValidationServer(http.StatusOK, http.StatusOK)
+	ts.Close()
+	withConfig(t, ts.URL, 0)
+
+	errs := ValidateConnectivity(context.Background(), NewApiClient(), NewIngestClient())
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 validation errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "not reachable") {
+		t.Errorf("Expected the API to be reported as unreachable, got: %v", errs[0])
+	}
+}
+
+func TestValidateConnectivity_WithoutIngest(t *testing.T) {
+	ts := newValidationServer(http.StatusOK, http.StatusUnauthorized)
+	defer ts.Close()
+	withConfig(t, ts.URL, 0)
+
+	errs := ValidateConnectivity(context.Background(), NewApiClient(), nil)
+	if len(errs) != 0 {
+		t.Errorf("Expected the ingest API not to be validated, got %v", errs)
+	}
+}
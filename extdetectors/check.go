@@ -38,6 +38,15 @@ const (
 	Stopped          = "STOPPED"
 )
 
+const (
+	mutedDetectorIgnore = "ignore"
+	mutedDetectorWarn   = "warn"
+	mutedDetectorFail   = "fail"
+	// mutingRecheckInterval bounds how often the muting rules are looked up during a step, as the lookup pages through
+	// all muting rules of the organization.
+	mutingRecheckInterval = time.Minute
+)
+
 type DetectorCheckState struct {
 	DetectorId            string
 	DetectorName          string
@@ -52,6 +61,16 @@ type DetectorCheckState struct {
 	// that a deviating state was observed during the step so the failure can be reported once the step ends.
 	DeviationSeen  bool
 	DeviationTitle string
+	// ConsecutiveApiErrors counts the Splunk API calls in a row that didn't respond with a success status.
+	ConsecutiveApiErrors int
+	// MutedDetector defines whether an active muting rule of the detector is ignored, reported as warning or fails the check.
+	MutedDetector string
+	// MutedWarningReported makes sure the warning about a muted detector is only reported once per step.
+	MutedWarningReported bool
+	// MutingCheckedAt, MutingRuleId and MutingRuleStopTime cache the muting rule of the detector between the lookups.
+	MutingCheckedAt    time.Time
+	MutingRuleId       string
+	MutingRuleStopTime int64
 }
 
 func NewDetectorStateCheckAction() action_kit_sdk.Action[DetectorCheckState] {
@@ -160,6 +179,30 @@ func (m *DetectorStateCheckAction) Describe() action_kit_api.ActionDescription {
 				Required:     new(false),
 				Order:        new(4),
 			},
+			{
+				Name:         "mutedDetector",
+				Label:        "Muted Detector",
+				Description:  new("What to do if the detector is muted by an active muting rule during the step. Muted detectors don't notify anyone about their incidents."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(mutedDetectorIgnore),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{
+						Label: "Ignore",
+						Value: mutedDetectorIgnore,
+					},
+					action_kit_api.ExplicitParameterOption{
+						Label: "Warn",
+						Value: mutedDetectorWarn,
+					},
+					action_kit_api.ExplicitParameterOption{
+						Label: "Fail",
+						Value: mutedDetectorFail,
+					},
+				}),
+				Advanced: new(true),
+				Required: new(false),
+				Order:    new(5),
+			},
 		},
 		Widgets: new([]action_kit_api.Widget{
 			action_kit_api.StateOverTimeWidget{
@@ -226,6 +269,11 @@ func (m *DetectorStateCheckAction) Prepare(_ context.Context, state *DetectorChe
 		state.FailEarly = extutil.ToBool(request.Config["failEarly"])
 	}
 
+	state.MutedDetector = mutedDetectorIgnore
+	if request.Config["mutedDetector"] != nil {
+		state.MutedDetector = fmt.Sprintf("%v", request.Config["mutedDetector"])
+	}
+
 	state.DetectorId = detectorId[0]
 	state.DetectorName = detectorName[0]
 	state.Start = start
@@ -255,6 +303,34 @@ func (m *DetectorStateCheckAction) Status(ctx context.Context, state *DetectorCh
 
 func DetectorCheckStatus(ctx context.Context, state *DetectorCheckState, client *resty.Client) (*action_kit_api.StatusResult, error) {
 	now := time.Now()
+	completed := now.After(state.End)
+
+	if (state.MutedDetector == mutedDetectorWarn || state.MutedDetector == mutedDetectorFail) && mutingCheckDue(state, now) {
+		mutingRule, err := findActiveMutingRule(ctx, client, state.DetectorId, now)
+		if err != nil {
+			state.ConsecutiveApiErrors++
+			log.Warn().Err(err).Msgf("Failed to check whether detector %s is muted (%d consecutive errors).", state.DetectorId, state.ConsecutiveApiErrors)
+			// Never pass the check without knowing whether the detector is muted.
+			if state.ConsecutiveApiErrors > config.Config.CheckApiErrorTolerance || completed {
+				return &action_kit_api.StatusResult{
+					Completed: true,
+					Error: new(action_kit_api.ActionKitError{
+						Title:  fmt.Sprintf("Failed to check whether detector '%s' is muted: %v", state.DetectorName, err),
+						Status: extutil.Ptr(action_kit_api.Errored),
+					}),
+				}, nil
+			}
+			return &action_kit_api.StatusResult{Completed: false}, nil
+		}
+		state.MutingCheckedAt = now
+		state.MutingRuleId = ""
+		state.MutingRuleStopTime = 0
+		if mutingRule != nil {
+			state.MutingRuleId = mutingRule.ID
+			state.MutingRuleStopTime = mutingRule.StopTime
+		}
+	}
+
 	var incidents []Incident
 
 	uri := "/v2/detector/" + state.DetectorId + "/incidents"
@@ -268,20 +344,33 @@ func DetectorCheckStatus(ctx context.Context, state *DetectorCheckState, client
 	}
 
 	if !res.IsSuccess() {
-		log.Err(err).Msgf("Splunk API responded with unexpected status code %d while retrieving Detector incidents for detector %s. Full response: %v", res.StatusCode(), state.DetectorId, res.String())
-	} else {
-		if state.CheckNewIncidentsOnly {
-			var filteredIncidents []Incident
-			for _, incident := range incidents {
-				if time.UnixMilli(incident.AnomalyStateUpdateTimestamp).After(state.Start) {
-					filteredIncidents = append(filteredIncidents, incident)
-				}
+		state.ConsecutiveApiErrors++
+		log.Warn().Msgf("Splunk API responded with unexpected status code %d while retrieving Detector incidents for detector %s (%d consecutive errors). Full response: %v", res.StatusCode(), state.DetectorId, state.ConsecutiveApiErrors, res.String())
+		// Never evaluate the expected state without incidents, as an empty list would look like "no incidents".
+		if state.ConsecutiveApiErrors > config.Config.CheckApiErrorTolerance || completed {
+			return &action_kit_api.StatusResult{
+				Completed: true,
+				Error: new(action_kit_api.ActionKitError{
+					Title: fmt.Sprintf("Splunk API responded with status code %d while retrieving incidents of detector '%s': %s",
+						res.StatusCode(), state.DetectorName, strings.TrimSpace(res.String())),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+	state.ConsecutiveApiErrors = 0
+
+	if state.CheckNewIncidentsOnly {
+		var filteredIncidents []Incident
+		for _, incident := range incidents {
+			if time.UnixMilli(incident.AnomalyStateUpdateTimestamp).After(state.Start) {
+				filteredIncidents = append(filteredIncidents, incident)
 			}
-			incidents = filteredIncidents
 		}
+		incidents = filteredIncidents
 	}
 
-	completed := now.After(state.End)
 	var checkError *action_kit_api.ActionKitError
 
 	// recordDeviation either fails immediately (fail early) or remembers the deviation so it can be
@@ -320,12 +409,6 @@ func DetectorCheckStatus(ctx context.Context, state *DetectorCheckState, client
 							state.DetectorName, state.ExpectedState))
 				}
 			}
-			if !state.FailEarly && completed && state.DeviationSeen {
-				checkError = new(action_kit_api.ActionKitError{
-					Title:  state.DeviationTitle,
-					Status: extutil.Ptr(action_kit_api.Failed),
-				})
-			}
 		} else if state.StateCheckMode == stateCheckModeAtLeastOnce {
 			for _, incident := range incidents {
 				if state.ExpectedState == incident.AnomalyState {
@@ -344,16 +427,66 @@ func DetectorCheckStatus(ctx context.Context, state *DetectorCheckState, client
 		}
 	}
 
+	var messages []action_kit_api.Message
+	if state.MutingRuleId != "" && (state.MutingRuleStopTime == 0 || now.UnixMilli() < state.MutingRuleStopTime) {
+		present := fmt.Sprintf("Detector '%s' is muted by muting rule %s, so its incidents don't notify anyone.", state.DetectorName, state.MutingRuleId)
+		if state.MutedDetector == mutedDetectorFail && checkError == nil {
+			recordDeviation(present,
+				fmt.Sprintf("Detector '%s' was muted by muting rule %s, so its incidents didn't notify anyone.", state.DetectorName, state.MutingRuleId))
+		} else if state.MutedDetector == mutedDetectorWarn && !state.MutedWarningReported {
+			state.MutedWarningReported = true
+			messages = append(messages, action_kit_api.Message{
+				Message: present,
+				Level:   extutil.Ptr(action_kit_api.Warn),
+			})
+		}
+	}
+
+	if !state.FailEarly && completed && state.DeviationSeen && checkError == nil {
+		checkError = new(action_kit_api.ActionKitError{
+			Title:  state.DeviationTitle,
+			Status: extutil.Ptr(action_kit_api.Failed),
+		})
+	}
+
 	var metrics []action_kit_api.Metric
 	for _, incident := range incidents {
 		metrics = append(metrics, *toMetric(state.DetectorId, state.DetectorName, incident, now))
 	}
 
-	return &action_kit_api.StatusResult{
+	result := &action_kit_api.StatusResult{
 		Completed: completed,
 		Error:     checkError,
 		Metrics:   new(metrics),
-	}, nil
+	}
+	if len(messages) > 0 {
+		result.Messages = new(messages)
+	}
+	return result, nil
+}
+
+// mutingCheckDue reports whether the muting rules have to be looked up again. Besides the first status, they are looked
+// up once the cached muting rule expired, as another rule may still mute the detector, and at a bounded interval to
+// notice muting rules created or removed during the step.
+func mutingCheckDue(state *DetectorCheckState, now time.Time) bool {
+	if state.MutingCheckedAt.IsZero() || now.Sub(state.MutingCheckedAt) >= mutingRecheckInterval {
+		return true
+	}
+	return state.MutingRuleId != "" && state.MutingRuleStopTime > 0 && now.UnixMilli() >= state.MutingRuleStopTime
+}
+
+// findActiveMutingRule returns an active muting rule of the detector, or nil if the detector isn't muted.
+func findActiveMutingRule(ctx context.Context, client *resty.Client, detectorId string, now time.Time) (*MutingRule, error) {
+	rules, err := GetMutingRules(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.IsActive(now) && rule.Mutes(detectorId) {
+			return &rule, nil
+		}
+	}
+	return nil, nil
 }
 
 func toMetric(detectorID string, detectorName string, incident Incident, now time.Time) *action_kit_api.Metric {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-resty/resty/v2"
 	actionApi "github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-splunk/config"
 )
 
 // dummyPrepareRequest creates a dummy PrepareActionRequestBody with a target and config.
@@ -365,3 +366,266 @@ func TestToMetric(t *testing.T) {
 		t.Errorf("Expected metric state 'danger' for anomaly state '%s', got '%s'", incident.AnomalyState, metric.Metric["state"])
 	}
 }
+
+func TestStatus_ApiErrorTolerance(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":401,"message":"Unauthorized"}`))
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalTolerance := config.Config.CheckApiErrorTolerance
+	config.Config.CheckApiErrorTolerance = 1
+	defer func() {
+		config.Config.CheckApiErrorTolerance = originalTolerance
+	}()
+
+	state := DetectorCheckState{
+		DetectorId:     "detector1",
+		DetectorName:   "Detector One",
+		Start:          time.Now().Add(-time.Minute),
+		End:            time.Now().Add(time.Minute),
+		ExpectedState:  NoIncident,
+		StateCheckMode: stateCheckModeAllTheTime,
+		FailEarly:      true,
+	}
+
+	// The first failure is tolerated, but must not be evaluated as "no incidents".
+	statusResult, err := DetectorCheckStatus(context.Background(), &state, RestyClient)
+	if err != nil {
+		t.Fatalf("DetectorCheckStatus returned error: %v", err)
+	}
+	if statusResult.Error != nil || statusResult.Completed {
+		t.Errorf("Expected the first API error to be tolerated, got %+v", statusResult)
+	}
+
+	// The second consecutive failure exceeds the tolerance.
+	statusResult, err = DetectorCheckStatus(context.Background(), &state, RestyClient)
+	if err != nil {
+		t.Fatalf("DetectorCheckStatus returned error: %v", err)
+	}
+	if statusResult.Error == nil {
+		t.Fatal("Expected an error once the tolerance is exceeded")
+	}
+	if *statusResult.Error.Status != actionApi.Errored {
+		t.Errorf("Expected status %s, got %s", actionApi.Errored, *statusResult.Error.Status)
+	}
+	if !strings.Contains(statusResult.Error.Title, "401") || !strings.Contains(statusResult.Error.Title, "Unauthorized") {
+		t.Errorf("Expected the title to contain the status code and Splunk error, got: %s", statusResult.Error.Title)
+	}
+}
+
+func TestStatus_ApiErrorAtEndOfStep(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalTolerance := config.Config.CheckApiErrorTolerance
+	config.Config.CheckApiErrorTolerance = 5
+	defer func() {
+		config.Config.CheckApiErrorTolerance = originalTolerance
+	}()
+
+	state := DetectorCheckState{
+		DetectorId:     "detector1",
+		DetectorName:   "Detector One",
+		Start:          time.Now().Add(-2 * time.Minute),
+		End:            time.Now().Add(-time.Minute),
+		ExpectedState:  NoIncident,
+		StateCheckMode: stateCheckModeAllTheTime,
+	}
+
+	// Even within the tolerance, a step ending on an API error must never succeed.
+	statusResult, err := DetectorCheckStatus(context.Background(), &state, RestyClient)
+	if err != nil {
+		t.Fatalf("DetectorCheckStatus returned error: %v", err)
+	}
+	if statusResult.Error == nil || *statusResult.Error.Status != actionApi.Errored {
+		t.Errorf("Expected an errored result, got %+v", statusResult.Error)
+	}
+}
+
+func TestStatus_MutedDetector(t *testing.T) {
+	now := time.Now()
+	rules := MutingRuleResponse{Count: 2, Results: []MutingRule{
+		{ID: "expired", StartTime: now.Add(-time.Hour).UnixMilli(), StopTime: now.Add(-time.Minute).UnixMilli(),
+			Filters: []MutingFilter{{Property: "sf_detectorId", PropertyValue: FilterValue{"detector1"}}}},
+		{ID: "rule1", StartTime: now.Add(-time.Minute).UnixMilli(), StopTime: now.Add(time.Hour).UnixMilli(),
+			Filters: []MutingFilter{{Property: "sf_detectorId", PropertyValue: FilterValue{"detector0", "detector1"}}}},
+	}}
+	var lookups int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/alertmuting" {
+			lookups++
+			json.NewEncoder(w).Encode(rules)
+			return
+		}
+		json.NewEncoder(w).Encode([]Incident{})
+	}))
+	defer ts.Close()
+	client := resty.New().SetBaseURL(ts.URL)
+
+	newState := func(mutedDetector string) *DetectorCheckState {
+		return &DetectorCheckState{
+			DetectorId:     "detector1",
+			DetectorName:   "Detector One",
+			Start:          now.Add(-time.Minute),
+			End:            now.Add(time.Minute),
+			ExpectedState:  NoIncident,
+			StateCheckMode: stateCheckModeAllTheTime,
+			FailEarly:      true,
+			MutedDetector:  mutedDetector,
+		}
+	}
+
+	result, _ := DetectorCheckStatus(context.Background(), newState(mutedDetectorIgnore), client)
+	if result.Error != nil || result.Messages != nil {
+		t.Errorf("Expected muting rules to be ignored, got %+v", result)
+	}
+
+	result, _ = DetectorCheckStatus(context.Background(), newState(mutedDetectorFail), client)
+	if result.Error == nil || !strings.Contains(result.Error.Title, "muted by muting rule rule1") {
+		t.Errorf("Expected the check to fail for a muted detector, got %v", result.Error)
+	}
+
+	state := newState(mutedDetectorWarn)
+	result, _ = DetectorCheckStatus(context.Background(), state, client)
+	if result.Error != nil || result.Messages == nil || *(*result.Messages)[0].Level != actionApi.Warn {
+		t.Errorf("Expected a warning for a muted detector, got %+v", result)
+	}
+	result, _ = DetectorCheckStatus(context.Background(), state, client)
+	if result.Messages != nil {
+		t.Error("Expected the warning to be reported only once")
+	}
+
+	lookups = 0
+	state = newState(mutedDetectorFail)
+	state.FailEarly = false
+	result, _ = DetectorCheckStatus(context.Background(), state, client)
+	if result.Error != nil {
+		t.Errorf("Expected no failure before the end of the step, got %v", result.Error)
+	}
+	state.End = time.Now().Add(-time.Second)
+	result, _ = DetectorCheckStatus(context.Background(), state, client)
+	if result.Error == nil || !strings.Contains(result.Error.Title, "was muted by muting rule rule1") {
+		t.Errorf("Expected the muted detector to be reported at the end of the step, got %v", result.Error)
+	}
+	if lookups != 1 {
+		t.Errorf("Expected the muting rules to be cached between the lookups, got %d lookups", lookups)
+	}
+}
+
+func TestStatus_MutedDetectorRecheck(t *testing.T) {
+	now := time.Now()
+	var rules MutingRuleResponse
+	var lookups int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/alertmuting" {
+			lookups++
+			json.NewEncoder(w).Encode(rules)
+			return
+		}
+		json.NewEncoder(w).Encode([]Incident{})
+	}))
+	defer ts.Close()
+	client := resty.New().SetBaseURL(ts.URL)
+	state := &DetectorCheckState{
+		DetectorId:     "detector1",
+		DetectorName:   "Detector One",
+		Start:          now.Add(-time.Minute),
+		End:            now.Add(time.Hour),
+		ExpectedState:  NoIncident,
+		StateCheckMode: stateCheckModeAllTheTime,
+		FailEarly:      true,
+		MutedDetector:  mutedDetectorFail,
+	}
+
+	if result, _ := DetectorCheckStatus(context.Background(), state, client); result.Error != nil || lookups != 1 {
+		t.Fatalf("Expected an unmuted detector after the first lookup, got %+v after %d lookups", result.Error, lookups)
+	}
+
+	// A muting rule created during the step is noticed once the recheck interval passed.
+	rules = MutingRuleResponse{Count: 1, Results: []MutingRule{{ID: "rule1", StartTime: now.UnixMilli()}}}
+	state.MutingCheckedAt = state.MutingCheckedAt.Add(-mutingRecheckInterval)
+	result, _ := DetectorCheckStatus(context.Background(), state, client)
+	if result.Error == nil || !strings.Contains(result.Error.Title, "muted by muting rule rule1") || lookups != 2 {
+		t.Errorf("Expected the new muting rule to fail the check, got %v after %d lookups", result.Error, lookups)
+	}
+
+	// Once the cached muting rule expired, the muting rules are looked up again right away.
+	rules = MutingRuleResponse{}
+	state.MutingRuleStopTime = time.Now().Add(-time.Second).UnixMilli()
+	if result, _ := DetectorCheckStatus(context.Background(), state, client); result.Error != nil || lookups != 3 || state.MutingRuleId != "" {
+		t.Errorf("Expected the expired muting rule to be cleared, got %+v after %d lookups", result.Error, lookups)
+	}
+}
+
+func TestStatus_MutedDetectorLookupErrors(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.CheckApiErrorTolerance = 1
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/alertmuting" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Incident{})
+	}))
+	defer ts.Close()
+	client := resty.New().SetBaseURL(ts.URL)
+	state := &DetectorCheckState{
+		DetectorId:     "detector1",
+		DetectorName:   "Detector One",
+		Start:          time.Now(),
+		End:            time.Now().Add(time.Minute),
+		ExpectedState:  NoIncident,
+		StateCheckMode: stateCheckModeAllTheTime,
+		FailEarly:      true,
+		MutedDetector:  mutedDetectorFail,
+	}
+
+	if result, _ := DetectorCheckStatus(context.Background(), state, client); result.Error != nil || result.Completed {
+		t.Errorf("Expected the first lookup error to be tolerated, got %+v", result)
+	}
+	result, _ := DetectorCheckStatus(context.Background(), state, client)
+	if result.Error == nil || *result.Error.Status != actionApi.Errored || !strings.Contains(result.Error.Title, "is muted") {
+		t.Errorf("Expected the check to error once the tolerance is exceeded, got %+v", result)
+	}
+}
+
+func TestMutingRule_Mutes(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		rule MutingRule
+		want bool
+	}{
+		{name: "detector filter", rule: MutingRule{Filters: []MutingFilter{{Property: "sf_detectorId", PropertyValue: FilterValue{"detector1"}}}}, want: true},
+		{name: "other detector", rule: MutingRule{Filters: []MutingFilter{{Property: "sf_detectorId", PropertyValue: FilterValue{"detector2"}}}}},
+		{name: "negated detector filter", rule: MutingRule{Filters: []MutingFilter{{Property: "sf_detectorId", PropertyValue: FilterValue{"detector1"}, Not: true}}}},
+		{name: "dimension filter only", rule: MutingRule{Filters: []MutingFilter{{Property: "service", PropertyValue: FilterValue{"checkout"}}}}},
+		{name: "no filters", rule: MutingRule{}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Mutes("detector1"); got != tt.want {
+				t.Errorf("Mutes() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	future := MutingRule{StartTime: now.Add(time.Minute).UnixMilli()}
+	indefinite := MutingRule{StartTime: now.Add(-time.Minute).UnixMilli()}
+	if future.IsActive(now) || !indefinite.IsActive(now) || indefinite.IsExpired(now) {
+		t.Error("Expected only started and unexpired rules to be active")
+	}
+}
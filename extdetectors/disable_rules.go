@@ -0,0 +1,246 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extdetectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type DetectorDisableRulesAction struct{}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[DetectorDisableRulesState]         = (*DetectorDisableRulesAction)(nil)
+	_ action_kit_sdk.ActionWithStop[DetectorDisableRulesState] = (*DetectorDisableRulesAction)(nil)
+)
+
+type DetectorDisableRulesState struct {
+	DetectorId   string
+	DetectorName string
+	DetectLabels []string
+	// OriginalDetector is the definition of the detector before the rules were disabled. It is kept in the state, so
+	// the rules can still be restored when the action is stopped after a restart of the extension.
+	OriginalDetector json.RawMessage
+}
+
+func NewDetectorDisableRulesAction() action_kit_sdk.Action[DetectorDisableRulesState] {
+	return &DetectorDisableRulesAction{}
+}
+
+func (m *DetectorDisableRulesAction) NewEmptyState() DetectorDisableRulesState {
+	return DetectorDisableRulesState{}
+}
+
+func (m *DetectorDisableRulesAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          fmt.Sprintf("%s.disable-rules", TargetType),
+		Label:       "Disable Detector Rules",
+		Description: "Disable rules of the detector for the duration of the step and restore them afterwards.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(targetIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:          TargetType,
+			QuantityRestriction: extutil.Ptr(action_kit_api.QuantityRestrictionAll),
+			SelectionTemplates: new([]action_kit_api.TargetSelectionTemplate{
+				{
+					Label:       "default",
+					Description: new("Find Detector by id"),
+					Query:       "splunk.detector.id=\"\"",
+				},
+			}),
+		}),
+		Technology:  new("Splunk"),
+		Category:    new("Splunk"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long the rules should be disabled."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("60s"),
+				Required:     new(true),
+				Order:        new(1),
+			},
+			{
+				Name:        "detectLabels",
+				Label:       "Rules",
+				Description: new("Detect labels of the rules to disable."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Required:    new(true),
+				Order:       new(2),
+			},
+		},
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (m *DetectorDisableRulesAction) Prepare(_ context.Context, state *DetectorDisableRulesState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	detectorId := request.Target.Attributes[attributeID]
+	if len(detectorId) == 0 {
+		return nil, new(extension_kit.ToError("Target is missing the '"+attributeID+"' attribute.", nil))
+	}
+	detectorName := request.Target.Attributes[attributeName]
+	if len(detectorName) == 0 {
+		return nil, new(extension_kit.ToError("Target is missing the '"+attributeName+"' attribute.", nil))
+	}
+
+	var detectLabels []string
+	for _, label := range extutil.ToStringArray(request.Config["detectLabels"]) {
+		if label = strings.TrimSpace(label); label != "" && !slices.Contains(detectLabels, label) {
+			detectLabels = append(detectLabels, label)
+		}
+	}
+	if len(detectLabels) == 0 {
+		return nil, new(extension_kit.ToError("At least one rule to disable is required.", nil))
+	}
+
+	state.DetectorId = detectorId[0]
+	state.DetectorName = detectorName[0]
+	state.DetectLabels = detectLabels
+	return nil, nil
+}
+
+func (m *DetectorDisableRulesAction) Start(ctx context.Context, state *DetectorDisableRulesState) (*action_kit_api.StartResult, error) {
+	return DetectorDisableRulesStart(ctx, state, RestyClient)
+}
+
+func (m *DetectorDisableRulesAction) Stop(ctx context.Context, state *DetectorDisableRulesState) (*action_kit_api.StopResult, error) {
+	return DetectorDisableRulesStop(ctx, state, RestyClient)
+}
+
+func DetectorDisableRulesStart(ctx context.Context, state *DetectorDisableRulesState, client *resty.Client) (*action_kit_api.StartResult, error) {
+	original, res, err := getDetectorDefinition(ctx, client, state.DetectorId)
+	if err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to retrieve detector '%s'.", state.DetectorName), err))
+	}
+	if !res.IsSuccess() {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Splunk API responded with status code %d while retrieving detector '%s': %s",
+			res.StatusCode(), state.DetectorName, strings.TrimSpace(res.String())), nil))
+	}
+
+	var detector Detector
+	if err := json.Unmarshal(original, &detector); err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to decode detector '%s'.", state.DetectorName), err))
+	}
+	disabled := make(map[string]bool, len(state.DetectLabels))
+	for _, label := range state.DetectLabels {
+		if !slices.ContainsFunc(detector.Rules, func(rule Rule) bool { return rule.DetectLabel == label }) {
+			return nil, new(extension_kit.ToError(fmt.Sprintf("Detector '%s' has no rule with detect label '%s'.", state.DetectorName, label), nil))
+		}
+		disabled[label] = true
+	}
+
+	// Remember the original definition before changing anything, so Stop can always restore it.
+	state.OriginalDetector = original
+	if err := updateRulesDisabled(ctx, client, state.DetectorId, original, disabled); err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to disable the rules of detector '%s'.", state.DetectorName), err))
+	}
+	log.Info().Msgf("Disabled rules %v of detector %s.", state.DetectLabels, state.DetectorId)
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{{
+			Message: fmt.Sprintf("Disabled the rules %s of detector '%s'.", strings.Join(state.DetectLabels, ", "), state.DetectorName),
+			Level:   extutil.Ptr(action_kit_api.Info),
+		}}),
+	}, nil
+}
+
+// DetectorDisableRulesStop restores the original disabled state of the selected rules. The current definition of the
+// detector is updated, so changes made to the detector in the meantime are kept.
+func DetectorDisableRulesStop(ctx context.Context, state *DetectorDisableRulesState, client *resty.Client) (*action_kit_api.StopResult, error) {
+	if len(state.OriginalDetector) == 0 {
+		return nil, nil
+	}
+
+	var original Detector
+	if err := json.Unmarshal(state.OriginalDetector, &original); err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to decode the original definition of detector '%s'.", state.DetectorName), err))
+	}
+	disabled := make(map[string]bool, len(state.DetectLabels))
+	for _, rule := range original.Rules {
+		if slices.Contains(state.DetectLabels, rule.DetectLabel) {
+			disabled[rule.DetectLabel] = rule.Disabled
+		}
+	}
+
+	current, res, err := getDetectorDefinition(ctx, client, state.DetectorId)
+	if err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to retrieve detector '%s'.", state.DetectorName), err))
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		log.Info().Msgf("Detector %s was deleted, there are no rules to restore.", state.DetectorId)
+		state.OriginalDetector = nil
+		return nil, nil
+	}
+	if !res.IsSuccess() {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Splunk API responded with status code %d while retrieving detector '%s': %s",
+			res.StatusCode(), state.DetectorName, strings.TrimSpace(res.String())), nil))
+	}
+
+	if err := updateRulesDisabled(ctx, client, state.DetectorId, current, disabled); err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to restore the rules of detector '%s'.", state.DetectorName), err))
+	}
+	log.Info().Msgf("Restored rules %v of detector %s.", state.DetectLabels, state.DetectorId)
+	state.OriginalDetector = nil
+	return nil, nil
+}
+
+func getDetectorDefinition(ctx context.Context, client *resty.Client, detectorId string) (json.RawMessage, *resty.Response, error) {
+	res, err := client.R().
+		SetContext(ctx).
+		Get("/v2/detector/" + detectorId)
+	if err != nil {
+		return nil, res, err
+	}
+	return res.Body(), res, nil
+}
+
+// updateRulesDisabled sets the disabled flag of the rules with the given detect labels and updates the detector. The
+// definition is changed as generic JSON, so fields not known to this extension are sent back unchanged.
+func updateRulesDisabled(ctx context.Context, client *resty.Client, detectorId string, definition json.RawMessage, disabled map[string]bool) error {
+	var detector map[string]any
+	if err := json.Unmarshal(definition, &detector); err != nil {
+		return fmt.Errorf("failed to decode detector: %w", err)
+	}
+	rules, _ := detector["rules"].([]any)
+	for _, rawRule := range rules {
+		rule, ok := rawRule.(map[string]any)
+		if !ok {
+			continue
+		}
+		if value, ok := disabled[extutil.ToString(rule["detectLabel"])]; ok {
+			rule["disabled"] = value
+		}
+	}
+
+	res, err := client.R().
+		SetContext(ctx).
+		SetBody(detector).
+		Put("/v2/detector/" + detectorId)
+	if err != nil {
+		return err
+	}
+	if !res.IsSuccess() {
+		return fmt.Errorf("splunk API responded with status code %d: %s", res.StatusCode(), strings.TrimSpace(res.String()))
+	}
+	return nil
+}
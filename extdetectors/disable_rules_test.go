@@ -0,0 +1,173 @@
+// disable_rules_test.go
+package extdetectors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	actionApi "github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+const testDetectorDefinition = `{
+	"id": "detector1",
+	"name": "Detector One",
+	"programText": "detect(when(A > 10)).publish('High')",
+	"someFutureField": {"kept": true},
+	"rules": [
+		{"detectLabel": "High", "disabled": false, "severity": "Critical"},
+		{"detectLabel": "Low", "disabled": true, "severity": "Minor"},
+		{"detectLabel": "Other", "disabled": false, "severity": "Info"}
+	]
+}`
+
+// detectorServer serves one detector definition and stores the updates made to it.
+type detectorServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	definition string
+	updates    int
+}
+
+func newDetectorServer(definition string) *detectorServer {
+	s := &detectorServer{definition: definition}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/detector/detector1", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(s.definition))
+	})
+	mux.HandleFunc("PUT /v2/detector/detector1", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.definition = string(body)
+		s.updates++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *detectorServer) rulesDisabled(t *testing.T) map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var detector Detector
+	if err := json.Unmarshal([]byte(s.definition), &detector); err != nil {
+		t.Fatalf("Failed to decode detector: %v", err)
+	}
+	disabled := make(map[string]bool)
+	for _, rule := range detector.Rules {
+		disabled[rule.DetectLabel] = rule.Disabled
+	}
+	return disabled
+}
+
+func TestDescribeDisableRules(t *testing.T) {
+	desc := (&DetectorDisableRulesAction{}).Describe()
+	if desc.Id != TargetType+".disable-rules" {
+		t.Errorf("Describe() Id = %s; want %s", desc.Id, TargetType+".disable-rules")
+	}
+	if desc.Kind != actionApi.Attack || desc.Stop == nil {
+		t.Error("Expected an attack with a stop endpoint to restore the rules")
+	}
+}
+
+func TestPrepareDisableRules(t *testing.T) {
+	action := &DetectorDisableRulesAction{}
+	state := action.NewEmptyState()
+	request := actionApi.PrepareActionRequestBody{
+		Target: &actionApi.Target{
+			Attributes: map[string][]string{
+				attributeID:   {"detector1"},
+				attributeName: {"Detector One"},
+			},
+		},
+		Config: map[string]any{
+			"duration":     float64(60000),
+			"detectLabels": []any{"High", " Low ", "High", ""},
+		},
+	}
+
+	if _, err := action.Prepare(context.Background(), &state, request); err != nil {
+		t.Fatalf("Prepare() returned error: %v", err)
+	}
+	if len(state.DetectLabels) != 2 || state.DetectLabels[0] != "High" || state.DetectLabels[1] != "Low" {
+		t.Errorf("Expected the trimmed and distinct detect labels, got %v", state.DetectLabels)
+	}
+
+	request.Config["detectLabels"] = []any{}
+	if _, err := action.Prepare(context.Background(), &state, request); err == nil {
+		t.Error("Expected an error without rules to disable")
+	}
+}
+
+func TestDisableRulesStartAndStop(t *testing.T) {
+	server := newDetectorServer(testDetectorDefinition)
+	defer server.Close()
+	client := resty.New().SetBaseURL(server.URL)
+	state := &DetectorDisableRulesState{DetectorId: "detector1", DetectorName: "Detector One", DetectLabels: []string{"High", "Low"}}
+
+	if _, err := DetectorDisableRulesStart(context.Background(), state, client); err != nil {
+		t.Fatalf("DetectorDisableRulesStart() returned error: %v", err)
+	}
+	if disabled := server.rulesDisabled(t); !disabled["High"] || !disabled["Low"] || disabled["Other"] {
+		t.Errorf("Expected only the selected rules to be disabled, got %v", disabled)
+	}
+	if len(state.OriginalDetector) == 0 {
+		t.Fatal("Expected the original definition in the state")
+	}
+
+	// Simulate a restart of the extension by restoring from the persisted state.
+	persisted, _ := json.Marshal(state)
+	var restored DetectorDisableRulesState
+	if err := json.Unmarshal(persisted, &restored); err != nil {
+		t.Fatalf("Failed to restore state: %v", err)
+	}
+
+	if _, err := DetectorDisableRulesStop(context.Background(), &restored, client); err != nil {
+		t.Fatalf("DetectorDisableRulesStop() returned error: %v", err)
+	}
+	if disabled := server.rulesDisabled(t); disabled["High"] || !disabled["Low"] || disabled["Other"] {
+		t.Errorf("Expected the original disabled state to be restored, got %v", disabled)
+	}
+	var detector map[string]any
+	_ = json.Unmarshal([]byte(server.definition), &detector)
+	if _, ok := detector["someFutureField"]; !ok {
+		t.Error("Expected unknown fields of the detector to be kept")
+	}
+
+	if _, err := DetectorDisableRulesStop(context.Background(), &restored, client); err != nil || server.updates != 2 {
+		t.Errorf("Expected a second stop to do nothing, got %d updates and error %v", server.updates, err)
+	}
+}
+
+func TestDisableRulesStart_UnknownRule(t *testing.T) {
+	server := newDetectorServer(testDetectorDefinition)
+	defer server.Close()
+	state := &DetectorDisableRulesState{DetectorId: "detector1", DetectorName: "Detector One", DetectLabels: []string{"Missing"}}
+
+	if _, err := DetectorDisableRulesStart(context.Background(), state, resty.New().SetBaseURL(server.URL)); err == nil {
+		t.Error("Expected an error for an unknown detect label")
+	}
+	if server.updates != 0 || len(state.OriginalDetector) != 0 {
+		t.Error("Expected the detector not to be changed")
+	}
+}
+
+func TestDisableRulesStop_DeletedDetector(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	state := &DetectorDisableRulesState{DetectorId: "detector1", DetectorName: "Detector One", DetectLabels: []string{"High"}, OriginalDetector: json.RawMessage(testDetectorDefinition)}
+
+	if _, err := DetectorDisableRulesStop(context.Background(), state, resty.New().SetBaseURL(server.URL)); err != nil {
+		t.Errorf("Expected nothing to restore for a deleted detector, got %v", err)
+	}
+}
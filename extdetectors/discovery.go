@@ -9,6 +9,7 @@ package extdetectors
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/discovery-kit/go/discovery_kit_api"
@@ -16,6 +17,7 @@ import (
 	"github.com/steadybit/discovery-kit/go/discovery_kit_sdk"
 	"github.com/steadybit/extension-kit/extbuild"
 	"github.com/steadybit/extension-splunk/config"
+	"strconv"
 	"time"
 )
 
@@ -30,6 +32,8 @@ const (
 	attributeStatus           = "splunk.detector.status"
 	attributeCreator          = "splunk.detector.creator"
 	attributeDetectorOrigin   = "splunk.detector.detectorOrigin"
+	defaultPageSize           = 100
+	defaultMaxResults         = 10000
 )
 
 type detectorDiscovery struct {
@@ -123,29 +127,40 @@ func (d *detectorDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDe
 }
 
 func (d *detectorDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_api.Target, error) {
-	return getAllDetectors(ctx, RestyClient), nil
+	return getAllDetectors(ctx, RestyClient)
 }
 
-func getAllDetectors(ctx context.Context, client *resty.Client) []discovery_kit_api.Target {
+// getAllDetectors retrieves the detectors page by page. If a page can't be retrieved, an error is returned instead of
+// the detectors found so far, so the cached discovery keeps the previous, complete result.
+func getAllDetectors(ctx context.Context, client *resty.Client) ([]discovery_kit_api.Target, error) {
 	result := make([]discovery_kit_api.Target, 0, 1000)
 
-	var splunkResponse Response
-	res, err := client.R().
-		SetContext(ctx).
-		SetResult(&splunkResponse).
-		Get("/v2/detector")
-
-	if err != nil {
-		log.Err(err).Msgf("Failed to retrieve detectors from Splunk. Full response: %v", res.String())
-		return result
+	pageSize := config.Config.DiscoveryPageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	maxResults := config.Config.DiscoveryMaxResults
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
 	}
 
-	if res.StatusCode() != 200 && res.StatusCode() != 404 {
-		log.Warn().Msgf("Splunk API responded with unexpected status code %d while retrieving detectors. Full response: %v",
-			res.StatusCode(),
-			res.String())
-		return result
-	} else {
+	for offset := 0; offset < maxResults; offset += pageSize {
+		var splunkResponse Response
+		res, err := client.R().
+			SetContext(ctx).
+			SetQueryParam("limit", strconv.Itoa(min(pageSize, maxResults-offset))).
+			SetQueryParam("offset", strconv.Itoa(offset)).
+			SetResult(&splunkResponse).
+			Get("/v2/detector")
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve detectors from Splunk: %w", err)
+		}
+
+		if res.StatusCode() != 200 && res.StatusCode() != 404 {
+			return nil, fmt.Errorf("splunk API responded with unexpected status code %d while retrieving detectors: %s", res.StatusCode(), res.String())
+		}
+
 		log.Trace().Msgf("Splunk response: %v", splunkResponse)
 
 		for _, detector := range splunkResponse.Results {
@@ -162,7 +177,16 @@ func getAllDetectors(ctx context.Context, client *resty.Client) []discovery_kit_
 					attributeDetectorOrigin: {detector.DetectorOrigin},
 				}})
 		}
+
+		// The last page is either shorter than the requested page size or reaches the total count reported by Splunk.
+		if len(splunkResponse.Results) < pageSize || (splunkResponse.Count > 0 && offset+len(splunkResponse.Results) >= splunkResponse.Count) {
+			break
+		}
+	}
+
+	if len(result) >= maxResults {
+		log.Warn().Msgf("Reached the maximum of %d detectors during discovery, remaining detectors are ignored.", maxResults)
 	}
 
-	return discovery_kit_commons.ApplyAttributeExcludes(result, config.Config.DiscoveryAttributesExcludesDetector)
+	return discovery_kit_commons.ApplyAttributeExcludes(result, config.Config.DiscoveryAttributesExcludesDetector), nil
 }
@@ -3,9 +3,12 @@ package extdetectors
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/go-resty/resty/v2"
@@ -111,7 +114,7 @@ func TestDiscoverTargets_ValidResponse(t *testing.T) {
 	}
 }
 
-// TestDiscoverTargets_UnexpectedStatus tests the behavior when the Splunk API returns an unexpected status code.
+// TestDiscoverTargets_UnexpectedStatus tests that an unexpected status code of the Splunk API is reported as an error.
 func TestDiscoverTargets_UnexpectedStatus(t *testing.T) {
 	// Create a test server that returns a 500 error.
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -125,8 +128,8 @@ func TestDiscoverTargets_UnexpectedStatus(t *testing.T) {
 
 	d := &detectorDiscovery{}
 	targets, err := d.DiscoverTargets(context.Background())
-	if err != nil {
-		t.Errorf("DiscoverTargets returned error: %v", err)
+	if err == nil {
+		t.Error("DiscoverTargets should return an error for an unexpected status code")
 	}
 	if len(targets) != 0 {
 		t.Errorf("DiscoverTargets returned %d targets; want 0", len(targets))
@@ -163,15 +166,15 @@ func (e *simulateClientErrorRoundTripper) RoundTrip(req *http.Request) (*http.Re
 	return nil, errors.New("forced error")
 }
 
-// TestDiscoverTargets_ClientError tests that an error during the HTTP call is handled gracefully.
+// TestDiscoverTargets_ClientError tests that an error during the HTTP call is reported.
 func TestDiscoverTargets_ClientError(t *testing.T) {
 	client := resty.New().SetTransport(&simulateClientErrorRoundTripper{})
 	RestyClient = client
 
 	d := &detectorDiscovery{}
 	targets, err := d.DiscoverTargets(context.Background())
-	if err != nil {
-		t.Errorf("DiscoverTargets returned error: %v", err)
+	if err == nil {
+		t.Error("DiscoverTargets should return an error if the request fails")
 	}
 	if len(targets) != 0 {
 		t.Errorf("DiscoverTargets returned %d targets; want 0", len(targets))
@@ -215,3 +218,115 @@ func TestDiscoverTargets_AttributeExclusion(t *testing.T) {
 		}
 	}
 }
+
+// TestDiscoverTargets_Pagination verifies that all pages are retrieved using limit/offset until the total count is reached.
+func TestDiscoverTargets_Pagination(t *testing.T) {
+	detectors := make([]Detector, 0, 5)
+	for i := 0; i < 5; i++ {
+		detectors = append(detectors, Detector{ID: fmt.Sprintf("det%d", i), Name: fmt.Sprintf("Detector %d", i)})
+	}
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+limit, len(detectors))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{Count: len(detectors), Results: detectors[offset:end]})
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalPageSize := config.Config.DiscoveryPageSize
+	config.Config.DiscoveryPageSize = 2
+	defer func() {
+		config.Config.DiscoveryPageSize = originalPageSize
+	}()
+
+	d := &detectorDiscovery{}
+	targets, err := d.DiscoverTargets(context.Background())
+	if err != nil {
+		t.Errorf("DiscoverTargets returned error: %v", err)
+	}
+	if len(targets) != 5 {
+		t.Errorf("DiscoverTargets returned %d targets; want 5", len(targets))
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests)
+	}
+	for i, target := range targets {
+		if target.Id != fmt.Sprintf("det%d", i) {
+			t.Errorf("Target[%d].Id = %s; want det%d", i, target.Id, i)
+		}
+	}
+}
+
+// TestDiscoverTargets_MaxResults verifies that discovery stops once the configured maximum is reached.
+func TestDiscoverTargets_MaxResults(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		results := make([]Detector, 0, limit)
+		for i := offset; i < offset+limit; i++ {
+			results = append(results, Detector{ID: fmt.Sprintf("det%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{Count: 100000, Results: results})
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalPageSize := config.Config.DiscoveryPageSize
+	originalMaxResults := config.Config.DiscoveryMaxResults
+	config.Config.DiscoveryPageSize = 4
+	config.Config.DiscoveryMaxResults = 10
+	defer func() {
+		config.Config.DiscoveryPageSize = originalPageSize
+		config.Config.DiscoveryMaxResults = originalMaxResults
+	}()
+
+	d := &detectorDiscovery{}
+	targets, err := d.DiscoverTargets(context.Background())
+	if err != nil {
+		t.Errorf("DiscoverTargets returned error: %v", err)
+	}
+	if len(targets) != 10 {
+		t.Errorf("DiscoverTargets returned %d targets; want 10", len(targets))
+	}
+}
+
+// TestDiscoverTargets_FailedPage verifies that a failed page fails the discovery instead of returning the previous pages.
+func TestDiscoverTargets_FailedPage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") != "0" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{Count: 4, Results: []Detector{{ID: "det0"}, {ID: "det1"}}})
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalPageSize := config.Config.DiscoveryPageSize
+	config.Config.DiscoveryPageSize = 2
+	defer func() {
+		config.Config.DiscoveryPageSize = originalPageSize
+	}()
+
+	d := &detectorDiscovery{}
+	targets, err := d.DiscoverTargets(context.Background())
+	if err == nil {
+		t.Error("DiscoverTargets should return an error if a page can't be retrieved")
+	}
+	if len(targets) != 0 {
+		t.Errorf("DiscoverTargets returned %d targets; want none instead of a partial result", len(targets))
+	}
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extdetectors
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	mutingPropertyDetectorId = "sf_detectorId"
+	// mutingRuleGracePeriod is added to the stop time of the muting rule, so the rule expires on its own shortly
+	// after the step should have ended, even if the extension never gets to stop the action.
+	mutingRuleGracePeriod = 5 * time.Minute
+)
+
+type DetectorMuteAction struct{}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[DetectorMuteState]         = (*DetectorMuteAction)(nil)
+	_ action_kit_sdk.ActionWithStop[DetectorMuteState] = (*DetectorMuteAction)(nil)
+)
+
+type DetectorMuteState struct {
+	DetectorId   string
+	DetectorName string
+	Duration     time.Duration
+	Filters      []MutingFilter
+	Description  string
+	// MutingRuleId is the id of the muting rule created by Start. It is kept in the state, so the rule can still be
+	// removed when the action is stopped after a restart of the extension.
+	MutingRuleId string
+}
+
+func NewDetectorMuteAction() action_kit_sdk.Action[DetectorMuteState] {
+	return &DetectorMuteAction{}
+}
+
+func (m *DetectorMuteAction) NewEmptyState() DetectorMuteState {
+	return DetectorMuteState{}
+}
+
+func (m *DetectorMuteAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          fmt.Sprintf("%s.mute", TargetType),
+		Label:       "Mute Detector Alerts",
+		Description: "Mute the alerts of the detector for the duration of the step, so nobody gets paged for the chaos you inject on purpose.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(targetIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:          TargetType,
+			QuantityRestriction: extutil.Ptr(action_kit_api.QuantityRestrictionAll),
+			SelectionTemplates: new([]action_kit_api.TargetSelectionTemplate{
+				{
+					Label:       "default",
+					Description: new("Find Detector by id"),
+					Query:       "splunk.detector.id=\"\"",
+				},
+			}),
+		}),
+		Technology:  new("Splunk"),
+		Category:    new("Splunk"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long the alerts should be muted."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("60s"),
+				Required:     new(true),
+				Order:        new(1),
+			},
+			{
+				Name:        "dimensionFilters",
+				Label:       "Dimension Filters",
+				Description: new("Only mute alerts whose dimensions match all of these values. Leave empty to mute all alerts of the detector."),
+				Type:        action_kit_api.ActionParameterTypeKeyValue,
+				Required:    new(false),
+				Order:       new(2),
+			},
+		},
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (m *DetectorMuteAction) Prepare(_ context.Context, state *DetectorMuteState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	detectorId := request.Target.Attributes[attributeID]
+	if len(detectorId) == 0 {
+		return nil, new(extension_kit.ToError("Target is missing the '"+attributeID+"' attribute.", nil))
+	}
+	detectorName := request.Target.Attributes[attributeName]
+	if len(detectorName) == 0 {
+		return nil, new(extension_kit.ToError("Target is missing the '"+attributeName+"' attribute.", nil))
+	}
+
+	duration := time.Millisecond * time.Duration(extutil.ToInt64(request.Config["duration"]))
+	if duration <= 0 {
+		return nil, new(extension_kit.ToError("Duration must be greater than 0.", nil))
+	}
+
+	filters := []MutingFilter{{Property: mutingPropertyDetectorId, PropertyValue: FilterValue{detectorId[0]}}}
+	if request.Config["dimensionFilters"] != nil {
+		dimensions, err := extutil.ToKeyValue(request.Config, "dimensionFilters")
+		if err != nil {
+			return nil, new(extension_kit.ToError("Failed to read the dimension filters.", err))
+		}
+		for _, key := range slices.Sorted(maps.Keys(dimensions)) {
+			if strings.TrimSpace(key) == "" {
+				return nil, new(extension_kit.ToError("Dimension filters must not have an empty dimension name.", nil))
+			}
+			filters = append(filters, MutingFilter{Property: key, PropertyValue: FilterValue{dimensions[key]}})
+		}
+	}
+
+	description := "Muted by Steadybit"
+	if request.ExecutionContext != nil && request.ExecutionContext.ExperimentKey != nil && request.ExecutionContext.ExecutionId != nil {
+		description = fmt.Sprintf("Muted by Steadybit experiment %s (execution %d)", *request.ExecutionContext.ExperimentKey, *request.ExecutionContext.ExecutionId)
+	}
+
+	state.DetectorId = detectorId[0]
+	state.DetectorName = detectorName[0]
+	state.Duration = duration
+	state.Filters = filters
+	state.Description = description
+	return nil, nil
+}
+
+func (m *DetectorMuteAction) Start(ctx context.Context, state *DetectorMuteState) (*action_kit_api.StartResult, error) {
+	return DetectorMuteStart(ctx, state, RestyClient)
+}
+
+func (m *DetectorMuteAction) Stop(ctx context.Context, state *DetectorMuteState) (*action_kit_api.StopResult, error) {
+	return DetectorMuteStop(ctx, state, RestyClient)
+}
+
+func DetectorMuteStart(ctx context.Context, state *DetectorMuteState, client *resty.Client) (*action_kit_api.StartResult, error) {
+	now := time.Now()
+	rule := MutingRule{
+		Description: state.Description,
+		Filters:     state.Filters,
+		StartTime:   now.UnixMilli(),
+		StopTime:    now.Add(state.Duration + mutingRuleGracePeriod).UnixMilli(),
+	}
+
+	var created MutingRule
+	res, err := client.R().
+		SetContext(ctx).
+		SetBody(rule).
+		SetResult(&created).
+		Post("/v2/alertmuting")
+	if err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to create a muting rule for detector '%s'.", state.DetectorName), err))
+	}
+	if !res.IsSuccess() {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Splunk API responded with status code %d while creating a muting rule for detector '%s': %s",
+			res.StatusCode(), state.DetectorName, strings.TrimSpace(res.String())), nil))
+	}
+	state.MutingRuleId = created.ID
+	log.Info().Msgf("Created muting rule %s for detector %s.", created.ID, state.DetectorId)
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{{
+			Message: fmt.Sprintf("Muted the alerts of detector '%s' with muting rule %s.", state.DetectorName, created.ID),
+			Level:   extutil.Ptr(action_kit_api.Info),
+		}}),
+	}, nil
+}
+
+// DetectorMuteStop removes the muting rule. Splunk only deletes muting rules that haven't started yet, so active rules
+// are ended by unmuting them, which expires the rule immediately.
+func DetectorMuteStop(ctx context.Context, state *DetectorMuteState, client *resty.Client) (*action_kit_api.StopResult, error) {
+	if state.MutingRuleId == "" {
+		return nil, nil
+	}
+
+	uri := "/v2/alertmuting/" + state.MutingRuleId
+	res, err := client.R().
+		SetContext(ctx).
+		Delete(uri)
+	if err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to delete muting rule %s of detector '%s'.", state.MutingRuleId, state.DetectorName), err))
+	}
+	if res.IsSuccess() || res.StatusCode() == http.StatusNotFound {
+		log.Info().Msgf("Deleted muting rule %s of detector %s.", state.MutingRuleId, state.DetectorId)
+		state.MutingRuleId = ""
+		return nil, nil
+	}
+
+	res, err = client.R().
+		SetContext(ctx).
+		Put(uri + "/unmute")
+	if err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to end muting rule %s of detector '%s'.", state.MutingRuleId, state.DetectorName), err))
+	}
+	if !res.IsSuccess() && res.StatusCode() != http.StatusNotFound {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Splunk API responded with status code %d while ending muting rule %s of detector '%s': %s",
+			res.StatusCode(), state.MutingRuleId, state.DetectorName, strings.TrimSpace(res.String())), nil))
+	}
+	log.Info().Msgf("Ended muting rule %s of detector %s.", state.MutingRuleId, state.DetectorId)
+	state.MutingRuleId = ""
+	return nil, nil
+}
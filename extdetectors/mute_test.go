@@ -0,0 +1,243 @@
+// mute_test.go
+package extdetectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	actionApi "github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extclient"
+)
+
+// mutingServer records the muting rule requests and answers them like the Splunk API.
+type mutingServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	created      []MutingRule
+	requests     []string
+	deleteStatus int
+}
+
+func newMutingServer(deleteStatus int) *mutingServer {
+	s := &mutingServer{deleteStatus: deleteStatus}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/alertmuting", func(w http.ResponseWriter, r *http.Request) {
+		var rule MutingRule
+		_ = json.NewDecoder(r.Body).Decode(&rule)
+		rule.ID = "rule1"
+		s.mu.Lock()
+		s.created = append(s.created, rule)
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(rule)
+	})
+	mux.HandleFunc("/v2/alertmuting/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(s.deleteStatus)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func dummyMutePrepareRequest(config map[string]any) actionApi.PrepareActionRequestBody {
+	return actionApi.PrepareActionRequestBody{
+		Target: &actionApi.Target{
+			Attributes: map[string][]string{
+				attributeID:   {"detector1"},
+				attributeName: {"Detector One"},
+			},
+		},
+		ExecutionContext: &actionApi.ExecutionContext{
+			ExperimentKey: new("ADM-1"),
+			ExecutionId:   new(42),
+		},
+		Config: config,
+	}
+}
+
+func TestDescribeMute(t *testing.T) {
+	desc := (&DetectorMuteAction{}).Describe()
+	if desc.Id != TargetType+".mute" {
+		t.Errorf("Describe() Id = %s; want %s", desc.Id, TargetType+".mute")
+	}
+	if desc.Kind != actionApi.Attack || desc.TimeControl != actionApi.TimeControlExternal {
+		t.Errorf("Expected an attack with external time control, got %s/%s", desc.Kind, desc.TimeControl)
+	}
+	if desc.Stop == nil {
+		t.Error("Expected a stop endpoint to remove the muting rule")
+	}
+}
+
+func TestPrepareMute(t *testing.T) {
+	action := &DetectorMuteAction{}
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, dummyMutePrepareRequest(map[string]any{
+		"duration": float64(60000),
+		"dimensionFilters": []any{
+			map[string]any{"key": "service", "value": "checkout"},
+			map[string]any{"key": "env", "value": "prod"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Prepare() returned error: %v", err)
+	}
+
+	if state.DetectorId != "detector1" || state.Duration != time.Minute {
+		t.Errorf("Unexpected state %+v", state)
+	}
+	if len(state.Filters) != 3 {
+		t.Fatalf("Expected the detector and two dimension filters, got %+v", state.Filters)
+	}
+	if filter := state.Filters[0]; filter.Property != "sf_detectorId" || filter.PropertyValue[0] != "detector1" {
+		t.Errorf("Expected the first filter to select the detector, got %+v", filter)
+	}
+	if filter := state.Filters[1]; filter.Property != "env" || filter.PropertyValue[0] != "prod" {
+		t.Errorf("Expected the dimension filters sorted by name, got %+v", state.Filters)
+	}
+	if state.Description != "Muted by Steadybit experiment ADM-1 (execution 42)" {
+		t.Errorf("Unexpected description %s", state.Description)
+	}
+}
+
+func TestPrepareMute_RejectsEmptyDimensionName(t *testing.T) {
+	action := &DetectorMuteAction{}
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, dummyMutePrepareRequest(map[string]any{
+		"duration":         float64(60000),
+		"dimensionFilters": []any{map[string]any{"key": " ", "value": "checkout"}},
+	}))
+	if err == nil {
+		t.Error("Expected an error for an empty dimension name")
+	}
+}
+
+func TestMuteStartAndStop(t *testing.T) {
+	tests := []struct {
+		name         string
+		deleteStatus int
+		wantRequests []string
+	}{
+		{name: "deleted", deleteStatus: http.StatusNoContent, wantRequests: []string{"POST /v2/alertmuting", "DELETE /v2/alertmuting/rule1"}},
+		{name: "already removed", deleteStatus: http.StatusNotFound, wantRequests: []string{"POST /v2/alertmuting", "DELETE /v2/alertmuting/rule1"}},
+		{name: "unmuted when active", deleteStatus: http.StatusBadRequest, wantRequests: []string{"POST /v2/alertmuting", "DELETE /v2/alertmuting/rule1", "PUT /v2/alertmuting/rule1/unmute"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMutingServer(tt.deleteStatus)
+			defer server.Close()
+			client := resty.New().SetBaseURL(server.URL)
+			state := &DetectorMuteState{
+				DetectorId:   "detector1",
+				DetectorName: "Detector One",
+				Duration:     time.Minute,
+				Filters:      []MutingFilter{{Property: "sf_detectorId", PropertyValue: FilterValue{"detector1"}}},
+				Description:  "Muted by Steadybit",
+			}
+
+			if _, err := DetectorMuteStart(context.Background(), state, client); err != nil {
+				t.Fatalf("DetectorMuteStart() returned error: %v", err)
+			}
+			if state.MutingRuleId != "rule1" {
+				t.Errorf("Expected the muting rule id in the state, got %q", state.MutingRuleId)
+			}
+			rule := server.created[0]
+			if rule.StopTime-rule.StartTime != (time.Minute + mutingRuleGracePeriod).Milliseconds() {
+				t.Errorf("Expected the rule to expire after the duration and grace period, got %+v", rule)
+			}
+
+			if _, err := DetectorMuteStop(context.Background(), state, client); err != nil {
+				t.Fatalf("DetectorMuteStop() returned error: %v", err)
+			}
+			if len(server.requests) != len(tt.wantRequests) {
+				t.Fatalf("Expected requests %v, got %v", tt.wantRequests, server.requests)
+			}
+			for i, request := range tt.wantRequests {
+				if server.requests[i] != request {
+					t.Errorf("Expected requests %v, got %v", tt.wantRequests, server.requests)
+				}
+			}
+			if state.MutingRuleId != "" {
+				t.Error("Expected the muting rule id to be cleared after the rule was removed")
+			}
+		})
+	}
+}
+
+func TestMuteStart_ApiError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	state := &DetectorMuteState{DetectorId: "detector1", DetectorName: "Detector One", Duration: time.Minute}
+
+	if _, err := DetectorMuteStart(context.Background(), state, resty.New().SetBaseURL(server.URL)); err == nil {
+		t.Error("Expected an error if the muting rule can't be created")
+	}
+	if state.MutingRuleId != "" {
+		t.Error("Expected no muting rule id without a created rule")
+	}
+}
+
+func TestMuteStart_DoesNotRetryCreatedRules(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.ApiRetryCount = 3
+	config.Config.ApiRetryWaitTime = time.Millisecond
+	config.Config.ApiRetryMaxWaitTime = time.Millisecond
+
+	var mu sync.Mutex
+	var created int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The rule is stored, but the response fails, like a timeout of a proxy in front of Splunk.
+		mu.Lock()
+		created++
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	config.Config.ApiBaseUrl = server.URL
+	state := &DetectorMuteState{DetectorId: "detector1", DetectorName: "Detector One", Duration: time.Minute}
+
+	if _, err := DetectorMuteStart(context.Background(), state, extclient.NewApiClient()); err == nil {
+		t.Error("Expected an error for the failed response")
+	}
+	if created != 1 {
+		t.Errorf("Expected a single muting rule to be created, got %d", created)
+	}
+}
+
+func TestMuteStop_WithoutRule(t *testing.T) {
+	if _, err := DetectorMuteStop(context.Background(), &DetectorMuteState{}, resty.New().SetBaseURL("http://localhost:1")); err != nil {
+		t.Errorf("Expected nothing to do without a muting rule, got %v", err)
+	}
+}
+
+func TestFilterValue(t *testing.T) {
+	single, _ := json.Marshal(FilterValue{"a"})
+	multiple, _ := json.Marshal(FilterValue{"a", "b"})
+	if string(single) != `"a"` || string(multiple) != `["a","b"]` {
+		t.Errorf("Unexpected encoding %s / %s", single, multiple)
+	}
+
+	var values []FilterValue
+	if err := json.Unmarshal([]byte(`["a", ["b", "c"]]`), &values); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if len(values[0]) != 1 || len(values[1]) != 2 {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extdetectors
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/extension-splunk/config"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetMutingRules retrieves the muting rules of the organization, page by page, up to the configured maximum of
+// discovery results.
+func GetMutingRules(ctx context.Context, client *resty.Client) ([]MutingRule, error) {
+	pageSize := config.Config.DiscoveryPageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	maxResults := config.Config.DiscoveryMaxResults
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+
+	var rules []MutingRule
+	for offset := 0; offset < maxResults; offset += pageSize {
+		var splunkResponse MutingRuleResponse
+		res, err := client.R().
+			SetContext(ctx).
+			SetQueryParam("limit", strconv.Itoa(min(pageSize, maxResults-offset))).
+			SetQueryParam("offset", strconv.Itoa(offset)).
+			SetResult(&splunkResponse).
+			Get("/v2/alertmuting")
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve muting rules from Splunk: %w", err)
+		}
+		if !res.IsSuccess() {
+			return nil, fmt.Errorf("splunk API responded with status code %d while retrieving muting rules: %s", res.StatusCode(), strings.TrimSpace(res.String()))
+		}
+
+		rules = append(rules, splunkResponse.Results...)
+
+		// The last page is either shorter than the requested page size or reaches the total count reported by Splunk.
+		if len(splunkResponse.Results) < pageSize || (splunkResponse.Count > 0 && offset+len(splunkResponse.Results) >= splunkResponse.Count) {
+			break
+		}
+	}
+	return rules, nil
+}
+
+// IsExpired reports whether the rule stopped muting alerts. Rules without stop time mute alerts until they are removed.
+func (r MutingRule) IsExpired(now time.Time) bool {
+	return r.StopTime > 0 && r.StopTime <= now.UnixMilli()
+}
+
+// IsActive reports whether the rule currently mutes alerts.
+func (r MutingRule) IsActive(now time.Time) bool {
+	return r.StartTime <= now.UnixMilli() && !r.IsExpired(now)
+}
+
+// DetectorIds returns the ids of the detectors the rule is restricted to.
+func (r MutingRule) DetectorIds() []string {
+	var ids []string
+	for _, filter := range r.Filters {
+		if filter.Property == mutingPropertyDetectorId && !filter.Not {
+			ids = append(ids, filter.PropertyValue...)
+		}
+	}
+	return ids
+}
+
+// Mutes reports whether the rule mutes the alerts of the detector. Rules without any filter mute all alerts. Rules
+// only filtering by dimensions are not considered, as they mute just some of the alerts of a detector.
+func (r MutingRule) Mutes(detectorId string) bool {
+	return len(r.Filters) == 0 || slices.Contains(r.DetectorIds(), detectorId)
+}
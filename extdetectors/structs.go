@@ -7,6 +7,8 @@
 
 package extdetectors
 
+import "encoding/json"
+
 type Response struct {
 	Count   int        `json:"count"`
 	Results []Detector `json:"results"`
@@ -125,3 +127,50 @@ type Input struct {
 	Key   map[string]string `json:"key,omitempty"`
 	Value string            `json:"value"`
 }
+
+type MutingRule struct {
+	ID          string         `json:"id,omitempty"`
+	Created     int64          `json:"created,omitempty"`
+	Creator     string         `json:"creator,omitempty"`
+	Description string         `json:"description"`
+	Filters     []MutingFilter `json:"filters"`
+	LastUpdated int64          `json:"lastUpdated,omitempty"`
+	StartTime   int64          `json:"startTime"`
+	StopTime    int64          `json:"stopTime"`
+}
+
+// MutingFilter matches alerts by a property, e.g. "sf_detectorId", or by a dimension of the alert.
+type MutingFilter struct {
+	Not           bool        `json:"NOT"`
+	Property      string      `json:"property"`
+	PropertyValue FilterValue `json:"propertyValue"`
+}
+
+// FilterValue is either a single value or a list of values in the Splunk API.
+type FilterValue []string
+
+func (v FilterValue) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+func (v *FilterValue) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*v = FilterValue{single}
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = values
+	return nil
+}
+
+type MutingRuleResponse struct {
+	Count   int          `json:"count"`
+	Results []MutingRule `json:"results"`
+}
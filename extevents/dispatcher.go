@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+const (
+	defaultQueueSize     = 1000
+	defaultBatchSize     = 50
+	defaultFlushInterval = 2 * time.Second
+	deliveryTimeout      = 30 * time.Second
+)
+
+type sendFunc[T any] func(ctx context.Context, items []T) error
+
+// dispatcher decouples the delivery of events, spans or datapoints from the event listener handlers. Items are queued
+// in a bounded queue and delivered in batches, once a batch is full or the flush interval elapsed. Batches which can't
+// be delivered are persisted to the optional spool and replayed, in order, before any newer batch.
+type dispatcher[T any] struct {
+	name          string
+	queue         chan T
+	batchSize     int
+	flushInterval time.Duration
+	retries       int
+	retryWaitTime time.Duration
+	send          sendFunc[T]
+	spool         *spool[T]
+	done          chan struct{}
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// newDispatcher creates a dispatcher for the items described by name, e.g. "events", used in log messages.
+func newDispatcher[T any](name string, send sendFunc[T], spool *spool[T]) *dispatcher[T] {
+	queueSize := config.Config.EventQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	batchSize := config.Config.EventBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	flushInterval := config.Config.EventFlushInterval
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+
+	return &dispatcher[T]{
+		name:          name,
+		queue:         make(chan T, queueSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		retries:       max(config.Config.EventDeliveryRetries, 0),
+		retryWaitTime: max(config.Config.ApiRetryWaitTime, 100*time.Millisecond),
+		send:          send,
+		spool:         spool,
+		done:          make(chan struct{}),
+	}
+}
+
+func (d *dispatcher[T]) start() {
+	go d.run()
+}
+
+// enqueue adds the item to the queue without blocking. Items are dropped if the queue is full or the dispatcher
+// was shut down.
+func (d *dispatcher[T]) enqueue(item T) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		log.Warn().Msgf("Dropping %s, delivery is shut down.", d.name)
+		d.drop(1)
+		return false
+	}
+	select {
+	case d.queue <- item:
+		return true
+	default:
+		log.Warn().Msgf("Dropping %s, the queue is full.", d.name)
+		d.drop(1)
+		return false
+	}
+}
+
+// shutdown stops accepting items and waits for the queued items to be delivered, at most for the given timeout.
+func (d *dispatcher[T]) shutdown(timeout time.Duration) {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		log.Debug().Msgf("Delivered all queued %s.", d.name)
+	case <-time.After(timeout):
+		log.Warn().Msgf("Timed out after %s while delivering the queued %s.", timeout, d.name)
+	}
+}
+
+func (d *dispatcher[T]) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, d.batchSize)
+	for {
+		select {
+		case item, ok := <-d.queue:
+			if !ok {
+				d.flush(batch, false)
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) >= d.batchSize {
+				d.flush(batch, true)
+				batch = make([]T, 0, d.batchSize)
+			}
+		case <-ticker.C:
+			d.flush(batch, true)
+			batch = make([]T, 0, d.batchSize)
+		}
+	}
+}
+
+// flush delivers the batch. While spooled batches are awaiting replay, the batch is spooled as well to preserve the
+// order of the events. No replay is attempted when replay is false, e.g. while shutting down.
+func (d *dispatcher[T]) flush(batch []T, replay bool) {
+	if d.spool != nil && d.spool.pending() {
+		if !replay || !d.spool.replay(d.send) {
+			d.store(batch)
+			return
+		}
+	}
+	if !d.deliver(batch) {
+		d.store(batch)
+	}
+}
+
+func (d *dispatcher[T]) store(batch []T) {
+	if len(batch) == 0 {
+		return
+	}
+	if d.spool == nil {
+		d.drop(len(batch))
+		return
+	}
+	if err := d.spool.store(batch); err != nil {
+		dropped := d.drop(len(batch))
+		log.Warn().Err(err).Msgf("Dropping %d %s, %d dropped in total.", len(batch), d.name, dropped)
+		return
+	}
+	log.Debug().Msgf("Spooled %d %s for later delivery.", len(batch), d.name)
+}
+
+func (d *dispatcher[T]) deliver(batch []T) bool {
+	if len(batch) == 0 {
+		return true
+	}
+
+	waitTime := d.retryWaitTime
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+		err := d.send(ctx, batch)
+		cancel()
+		if err == nil {
+			return true
+		}
+		if attempt >= d.retries {
+			log.Err(err).Msgf("Failed to deliver %d %s after %d attempts.", len(batch), d.name, attempt+1)
+			return false
+		}
+		log.Debug().Err(err).Msgf("Failed to deliver %d %s, retrying in %s.", len(batch), d.name, waitTime)
+		time.Sleep(waitTime)
+		waitTime *= 2
+	}
+}
+
+// drop counts dropped items and returns the number of items this dispatcher dropped in total.
+func (d *dispatcher[T]) drop(count int) int64 {
+	stats.droppedEvents.Add(int64(count))
+	return d.dropped.Add(int64(count))
+}
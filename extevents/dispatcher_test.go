@@ -0,0 +1,163 @@
+// dispatcher_test.go
+package extevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+// recordingSender collects the delivered batches and fails the first failures calls.
+type recordingSender struct {
+	mu       sync.Mutex
+	batches  [][]*Event
+	calls    int
+	failures int
+}
+
+func (s *recordingSender) send(_ context.Context, events []*Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("forced error")
+	}
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *recordingSender) batchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, 0, len(s.batches))
+	for _, batch := range s.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func withDispatcherConfig(t *testing.T, batchSize int, flushInterval time.Duration, retries int) {
+	original := config.Config
+	config.Config.EventQueueSize = 10
+	config.Config.EventBatchSize = batchSize
+	config.Config.EventFlushInterval = flushInterval
+	config.Config.EventDeliveryRetries = retries
+	config.Config.ApiRetryWaitTime = time.Millisecond
+	t.Cleanup(func() {
+		config.Config = original
+	})
+}
+
+func TestDispatcher_BatchesBySize(t *testing.T) {
+	withDispatcherConfig(t, 2, time.Hour, 0)
+	sender := &recordingSender{}
+	d := newDispatcher("events", sender.send, nil)
+	d.start()
+
+	for i := 0; i < 5; i++ {
+		d.enqueue(&Event{EventType: "test"})
+	}
+	d.shutdown(time.Second)
+
+	if sizes := sender.batchSizes(); len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("Expected batches of [2 2 1], got %v", sizes)
+	}
+}
+
+func TestDispatcher_FlushesAfterInterval(t *testing.T) {
+	withDispatcherConfig(t, 100, 10*time.Millisecond, 0)
+	sender := &recordingSender{}
+	d := newDispatcher("events", sender.send, nil)
+	d.start()
+	defer d.shutdown(time.Second)
+
+	d.enqueue(&Event{EventType: "test"})
+
+	deadline := time.Now().Add(time.Second)
+	for len(sender.batchSizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sizes := sender.batchSizes(); len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("Expected the event to be flushed after the interval, got %v", sizes)
+	}
+}
+
+func TestDispatcher_RetriesFailedBatches(t *testing.T) {
+	withDispatcherConfig(t, 1, time.Hour, 2)
+	sender := &recordingSender{failures: 2}
+	d := newDispatcher("events", sender.send, nil)
+	d.start()
+
+	d.enqueue(&Event{EventType: "test"})
+	d.shutdown(time.Second)
+
+	if sender.calls != 3 {
+		t.Errorf("Expected 3 delivery attempts, got %d", sender.calls)
+	}
+	if sizes := sender.batchSizes(); len(sizes) != 1 {
+		t.Errorf("Expected the batch to be delivered eventually, got %v", sizes)
+	}
+}
+
+func TestDispatcher_DropsWhenFullOrShutDown(t *testing.T) {
+	withDispatcherConfig(t, 100, time.Hour, 0)
+	config.Config.EventQueueSize = 1
+	d := newDispatcher("events", (&recordingSender{}).send, nil)
+
+	// The dispatcher isn't started, so the queue is never consumed.
+	if !d.enqueue(&Event{EventType: "first"}) {
+		t.Error("Expected the first event to be queued")
+	}
+	if d.enqueue(&Event{EventType: "second"}) {
+		t.Error("Expected the second event to be dropped as the queue is full")
+	}
+
+	d.start()
+	d.shutdown(time.Second)
+	if d.enqueue(&Event{EventType: "third"}) {
+		t.Error("Expected events to be dropped after the shutdown")
+	}
+}
+
+func TestHandlePostEvent_PostsBatch(t *testing.T) {
+	var received []Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/event" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode events: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := resty.New().SetBaseURL(ts.URL)
+	err := handlePostEvent(context.Background(), client, []*Event{{EventType: "a"}, {EventType: "b"}})
+	if err != nil {
+		t.Fatalf("handlePostEvent returned error: %v", err)
+	}
+	if len(received) != 2 || received[0].EventType != "a" || received[1].EventType != "b" {
+		t.Errorf("Expected both events in one request, got %v", received)
+	}
+}
+
+func TestHandlePostEvent_UnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	err := handlePostEvent(context.Background(), resty.New().SetBaseURL(ts.URL), []*Event{{EventType: "a"}})
+	if err == nil {
+		t.Error("Expected an error for a non-successful status code")
+	}
+}
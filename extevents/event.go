@@ -12,29 +12,90 @@ import (
 	"github.com/steadybit/event-kit/go/event_kit_api"
 	extension_kit "github.com/steadybit/extension-kit"
 	"github.com/steadybit/extension-kit/exthttp"
+	"github.com/steadybit/extension-kit/extsignals"
+	"github.com/steadybit/extension-splunk/config"
 	"maps"
 	"net/http"
-	"sync"
+	"os"
+	"slices"
 	"time"
 )
 
 func RegisterEventListenerHandlers() {
-	exthttp.RegisterHttpHandler("/events/experiment-started", handle(onExperiment))
-	exthttp.RegisterHttpHandler("/events/experiment-completed", handle(onExperimentCompleted))
-	exthttp.RegisterHttpHandler("/events/experiment-step-started", handle(onExperimentStep))
-	//exthttp.RegisterHttpHandler("/events/experiment-step-completed", handle(onExperimentStep))
-	exthttp.RegisterHttpHandler("/events/experiment-target-started", handle(onExperimentTarget))
-	exthttp.RegisterHttpHandler("/events/experiment-target-completed", handle(onExperimentTarget))
+	mapping, err := loadDimensionMapping(config.Config.DimensionMappingFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load the dimension mapping.")
+	}
+	dimensionMapping = mapping
+
+	eventRedaction, err = newRedaction(config.Config.EventRedactionKeys, config.Config.EventRedactionValues, config.Config.EventRedactionMode)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure the event redaction.")
+	}
+
+	stepExecutions = newStepExecutionStore(config.Config.StepExecutionTtl, config.Config.StepExecutionMaxEntries)
+	filter = newEventFilter()
+	sinks = newSinks()
+	extsignals.AddSignalHandler(extsignals.SignalHandler{
+		Handler: func(_ os.Signal) {
+			shutdownSinks(sinks, drainTimeout)
+		},
+		// Drain after the extension HTTP server stopped accepting new events.
+		Order: extsignals.OrderStopExtensionHttp + 1,
+		Name:  "DrainSplunkEvents",
+	})
+
+	for _, listener := range eventListeners {
+		exthttp.RegisterHttpHandler(listener.path, handle(listener.handler))
+	}
 }
 
-const (
-	category = "USER_DEFINED"
-)
+// GetEventListeners returns the event listeners of the registered handlers for the extension list.
+func GetEventListeners() []event_kit_api.EventListener {
+	listeners := make([]event_kit_api.EventListener, 0, len(eventListeners))
+	for _, listener := range eventListeners {
+		listeners = append(listeners, event_kit_api.EventListener{
+			Method:   "POST",
+			Path:     listener.path,
+			ListenTo: listener.listenTo,
+		})
+	}
+	return listeners
+}
 
+// The event names of the lifecycle phases. They decide which handler receives an event and which event type it gets.
 var (
-	stepExecutions = sync.Map{}
+	experimentStartedEvents   = []string{"experiment.execution.created"}
+	experimentCompletedEvents = []string{"experiment.execution.completed", "experiment.execution.failed", "experiment.execution.canceled", "experiment.execution.errored"}
+	stepStartedEvents         = []string{"experiment.execution.step-started"}
+	stepCompletedEvents       = []string{"experiment.execution.step-completed", "experiment.execution.step-failed", "experiment.execution.step-errored", "experiment.execution.step-canceled"}
+	targetStartedEvents       = []string{"experiment.execution.target-started"}
+	targetCompletedEvents     = []string{"experiment.execution.target-completed", "experiment.execution.target-canceled", "experiment.execution.target-errored", "experiment.execution.target-failed"}
+)
+
+type eventListener struct {
+	path     string
+	listenTo []string
+	handler  eventHandler
+}
+
+var eventListeners = []eventListener{
+	{path: "/events/experiment-started", listenTo: experimentStartedEvents, handler: onExperiment},
+	{path: "/events/experiment-completed", listenTo: experimentCompletedEvents, handler: onExperiment},
+	{path: "/events/experiment-step-started", listenTo: stepStartedEvents, handler: onExperimentStep},
+	{path: "/events/experiment-step-completed", listenTo: stepCompletedEvents, handler: onExperimentStepCompleted},
+	{path: "/events/experiment-target-started", listenTo: targetStartedEvents, handler: onExperimentTarget},
+	{path: "/events/experiment-target-completed", listenTo: targetCompletedEvents, handler: onExperimentTarget},
+}
+
+const (
+	category         = "USER_DEFINED"
+	defaultEventType = "Steadybit_Event"
+	drainTimeout     = 5 * time.Second
 )
 
+var stepExecutions = newStepExecutionStore(defaultStepExecutionTtl, defaultStepExecutionMaxEntries)
+
 var RestyClient *resty.Client
 
 type eventHandler func(event event_kit_api.EventRequestBody) (*Event, error)
@@ -48,10 +109,16 @@ func handle(handler eventHandler) func(w http.ResponseWriter, r *http.Request, b
 			return
 		}
 
+		// The step executions are tracked before filtering, as the filter looks up the action of target events.
+		trackStepExecutions(event)
+		if !filter.accepts(event) {
+			log.Debug().Msgf("Skipping event %s %s, it doesn't match the event filter.", event.EventName, event.Id)
+			exthttp.WriteBody(w, "{}")
+			return
+		}
+
 		if request, err := handler(event); err == nil {
-			if request != nil {
-				handlePostEvent(r.Context(), RestyClient, request)
-			}
+			dispatch(sinks, event, eventRedaction.applyToEvent(request))
 		} else {
 			exthttp.WriteError(w, extension_kit.ToError(err.Error(), err))
 			return
@@ -61,44 +128,48 @@ func handle(handler eventHandler) func(w http.ResponseWriter, r *http.Request, b
 	}
 }
 
+func trackStepExecutions(event event_kit_api.EventRequestBody) {
+	switch {
+	case slices.Contains(stepStartedEvents, event.EventName):
+		if event.ExperimentStepExecution != nil {
+			stepExecutions.put(*event.ExperimentStepExecution)
+		}
+	case slices.Contains(experimentCompletedEvents, event.EventName):
+		if event.ExperimentExecution == nil {
+			return
+		}
+		if deleted := stepExecutions.deleteExecution(event.ExperimentExecution.ExecutionId); deleted > 0 {
+			log.Debug().Msgf("Deleted %d step executions for execution id %.0f", deleted, event.ExperimentExecution.ExecutionId)
+		}
+	}
+}
+
 func onExperiment(event event_kit_api.EventRequestBody) (*Event, error) {
 	tags := getEventBaseTags(event)
 	maps.Copy(tags, getExecutionTags(event))
 
-	return &Event{
-		Category:   category,
-		EventType:  "Steadybit_Event",
-		Properties: tags,
-		Timestamp:  event.EventTime.UnixMilli(),
-	}, nil
+	return newEvent(event, tags, nil), nil
 }
 
-func onExperimentCompleted(event event_kit_api.EventRequestBody) (*Event, error) {
-	stepExecutions.Range(func(key, value any) bool {
-		stepExecution := value.(event_kit_api.ExperimentStepExecution)
-		if stepExecution.ExecutionId == event.ExperimentExecution.ExecutionId {
-			log.Debug().Msgf("Delete step execution data for id %.0f", stepExecution.ExecutionId)
-			stepExecutions.Delete(key)
-		}
-		return true
-	})
+func onExperimentStep(event event_kit_api.EventRequestBody) (*Event, error) {
+	tags := getEventBaseTags(event)
+	maps.Copy(tags, getExecutionTags(event))
+	maps.Copy(tags, getStepTags(*event.ExperimentStepExecution))
 
-	return onExperiment(event)
+	return newEvent(event, tags, nil), nil
 }
 
-func onExperimentStep(event event_kit_api.EventRequestBody) (*Event, error) {
+func onExperimentStepCompleted(event event_kit_api.EventRequestBody) (*Event, error) {
+	if event.ExperimentStepExecution == nil {
+		return nil, nil
+	}
+
 	tags := getEventBaseTags(event)
 	maps.Copy(tags, getExecutionTags(event))
 	maps.Copy(tags, getStepTags(*event.ExperimentStepExecution))
+	maps.Copy(tags, getStepOutcomeTags(event))
 
-	stepExecutions.Store(event.ExperimentStepExecution.Id, *event.ExperimentStepExecution)
-
-	return &Event{
-		Category:   category,
-		EventType:  "Steadybit_Event",
-		Properties: tags,
-		Timestamp:  event.EventTime.UnixMilli(),
-	}, nil
+	return newEvent(event, tags, nil), nil
 }
 
 func getEventBaseTags(event event_kit_api.EventRequestBody) map[string]string {
@@ -159,11 +230,41 @@ func getStepTags(step event_kit_api.ExperimentStepExecution) map[string]string {
 	return tags
 }
 
+func getStepOutcomeTags(event event_kit_api.EventRequestBody) map[string]string {
+	tags := make(map[string]string)
+	step := event.ExperimentStepExecution
+
+	tags["step_state"] = string(step.State)
+	if step.StartedTime != nil {
+		tags["step_started_time"] = step.StartedTime.Format(time.RFC3339)
+	}
+	if step.EndedTime != nil {
+		tags["step_ended_time"] = step.EndedTime.Format(time.RFC3339)
+	}
+	if step.StartedTime != nil && step.EndedTime != nil {
+		tags["step_duration_ms"] = fmt.Sprintf("%d", step.EndedTime.Sub(*step.StartedTime).Milliseconds())
+	}
+
+	// The step doesn't carry an error message itself, the reason of the experiment execution names the failing step.
+	// Canceled and skipped steps didn't fail, the reason of the experiment execution doesn't belong to them.
+	failed := step.State == event_kit_api.ExperimentStepExecutionStateFailed || step.State == event_kit_api.ExperimentStepExecutionStateErrored
+	if failed && event.ExperimentExecution != nil {
+		if event.ExperimentExecution.Reason != nil {
+			tags["step_error"] = *event.ExperimentExecution.Reason
+		}
+		if event.ExperimentExecution.ReasonDetails != nil {
+			tags["step_error_details"] = *event.ExperimentExecution.ReasonDetails
+		}
+	}
+
+	return tags
+}
+
 func getTargetTags(target event_kit_api.ExperimentStepTargetExecution) map[string]string {
 	tags := make(map[string]string)
 
 	tags["execution_id"] = fmt.Sprintf("%.0f", target.ExecutionId)
-	tags["execution_id"] = target.ExperimentKey
+	tags["experiment_key"] = target.ExperimentKey
 	tags["execution_state"] = string(target.State)
 
 	if target.StartedTime != nil {
@@ -178,46 +279,7 @@ func getTargetTags(target event_kit_api.ExperimentStepTargetExecution) map[strin
 }
 
 func getTargetDimensions(target event_kit_api.ExperimentStepTargetExecution) map[string]string {
-	dimensions := make(map[string]string)
-	const clusterNameSteadybitAttribute = "k8s.cluster-name"
-
-	if _, ok := target.TargetAttributes[clusterNameSteadybitAttribute]; ok {
-		translateToSplunk(dimensions, target, clusterNameSteadybitAttribute, "k8s.cluster.name")
-		translateToSplunk(dimensions, target, "k8s.namespace", "k8s.namespace.name")
-		translateToSplunk(dimensions, target, "k8s.deployment", "k8s.deployment.name")
-		translateToSplunk(dimensions, target, "k8s.pod.name", "clustername")
-		translateToSplunk(dimensions, target, "k8s.container.name", "k8s.container.name")
-	}
-
-	getHostnameDimension(dimensions, target)
-	translateToSplunk(dimensions, target, "container.id.stripped", "container.id")
-
-	if _, ok := target.TargetAttributes["aws.region"]; ok {
-		//AWS tags
-		dimensions["cloud.provider"] = "aws"
-		translateToSplunk(dimensions, target, "aws.region", "cloud.region")
-		translateToSplunk(dimensions, target, "aws.zone", "cloud.availability_zone")
-		translateToSplunk(dimensions, target, "aws.account", "cloud.account.id")
-	}
-
-	return dimensions
-}
-
-func getHostnameDimension(dimensions map[string]string, target event_kit_api.ExperimentStepTargetExecution) {
-	const splunkHostDimensionName = "host.name"
-
-	translateToSplunk(dimensions, target, "container.host", splunkHostDimensionName)
-	translateToSplunk(dimensions, target, "container.host", splunkHostDimensionName)
-	translateToSplunk(dimensions, target, "host.hostname", splunkHostDimensionName)
-	translateToSplunk(dimensions, target, "application.hostname", splunkHostDimensionName)
-}
-
-func translateToSplunk(dimensions map[string]string, target event_kit_api.ExperimentStepTargetExecution, steadybitAttribute string, splunkDimension string) {
-	if values, ok := target.TargetAttributes[steadybitAttribute]; ok {
-		if (len(values)) == 1 {
-			dimensions[splunkDimension] = values[0]
-		}
-	}
+	return dimensionMapping.apply(target.TargetAttributes)
 }
 
 func parseBodyToEventRequestBody(body []byte) (event_kit_api.EventRequestBody, error) {
@@ -226,14 +288,10 @@ func parseBodyToEventRequestBody(body []byte) (event_kit_api.EventRequestBody, e
 	return event, err
 }
 
-func handlePostEvent(ctx context.Context, client *resty.Client, event *Event) {
-	events := make([]*Event, 0)
-	events = append(events, event)
-
+func handlePostEvent(ctx context.Context, client *resty.Client, events []*Event) error {
 	eventBytes, err := json.Marshal(events)
 	if err != nil {
-		log.Err(err).Msgf("Failed to marshal event %v. Full response: %v", event, err)
-		return
+		return fmt.Errorf("failed to marshal events: %w", err)
 	}
 
 	res, err := client.R().
@@ -242,13 +300,15 @@ func handlePostEvent(ctx context.Context, client *resty.Client, event *Event) {
 		Post("/v2/event")
 
 	if err != nil {
-		log.Err(err).Msgf("Failed to post event, body: %v. Full response: %v", eventBytes, res.String())
-		return
+		return fmt.Errorf("failed to post events: %w", err)
 	}
 
 	if !res.IsSuccess() {
-		log.Err(err).Msgf("Splunk Ingest API responded with unexpected status code %d while posting events. Full response: %v", res.StatusCode(), res.String())
+		return fmt.Errorf("splunk ingest API responded with unexpected status code %d while posting events. Full response: %v", res.StatusCode(), res.String())
 	}
+
+	log.Debug().Msgf("Posted %d events to Splunk.", len(events))
+	return nil
 }
 
 func onExperimentTarget(event event_kit_api.EventRequestBody) (*Event, error) {
@@ -256,27 +316,81 @@ func onExperimentTarget(event event_kit_api.EventRequestBody) (*Event, error) {
 		return nil, nil
 	}
 
-	var v, ok = stepExecutions.Load(event.ExperimentStepTargetExecution.StepExecutionId)
+	stepExecution, ok := stepExecutions.get(event.ExperimentStepTargetExecution.StepExecutionId.String())
 	if !ok {
-		log.Warn().Msgf("Could not find step infos for step execution id %s", event.ExperimentStepTargetExecution.StepExecutionId)
-		return nil, nil
+		// The step started event was missed or evicted, so we can't tell whether the target was attacked. Rather
+		// forward the event without the step information than losing it.
+		log.Warn().Msgf("Could not find step infos for step execution id %s, sending event without step infos", event.ExperimentStepTargetExecution.StepExecutionId)
+		stats.unknownStepExecutions.Add(1)
+		targetEvent := newTargetEvent(event)
+		targetEvent.Properties["step_unknown"] = "true"
+		return targetEvent, nil
 	}
-	stepExecution := v.(event_kit_api.ExperimentStepExecution)
 
 	if stepExecution.ActionKind != nil && *stepExecution.ActionKind == event_kit_api.Attack {
-		tags := getEventBaseTags(event)
-		maps.Copy(tags, getExecutionTags(event))
-		maps.Copy(tags, getTargetTags(*event.ExperimentStepTargetExecution))
-		dimensions := getTargetDimensions(*event.ExperimentStepTargetExecution)
-
-		return &Event{
-			Category:   category,
-			EventType:  "Steadybit_Event",
-			Dimensions: dimensions,
-			Properties: tags,
-			Timestamp:  event.EventTime.UnixMilli(),
-		}, nil
+		return newTargetEvent(event), nil
 	}
 
 	return nil, nil
 }
+
+func newTargetEvent(event event_kit_api.EventRequestBody) *Event {
+	tags := getEventBaseTags(event)
+	maps.Copy(tags, getExecutionTags(event))
+	maps.Copy(tags, getTargetTags(*event.ExperimentStepTargetExecution))
+	dimensions := getTargetDimensions(*event.ExperimentStepTargetExecution)
+
+	return newEvent(event, tags, dimensions)
+}
+
+// newEvent creates the event of the lifecycle phase the request belongs to. The static properties and dimensions are
+// added to every event, values derived from the request take precedence.
+func newEvent(event event_kit_api.EventRequestBody, properties map[string]string, dimensions map[string]string) *Event {
+	eventCategory := config.Config.EventCategory
+	if eventCategory == "" {
+		eventCategory = category
+	}
+
+	eventProperties := maps.Clone(config.Config.EventStaticProperties)
+	if eventProperties == nil {
+		eventProperties = make(map[string]string, len(properties))
+	}
+	maps.Copy(eventProperties, properties)
+
+	eventDimensions := dimensions
+	if len(config.Config.EventStaticDimensions) > 0 {
+		eventDimensions = maps.Clone(config.Config.EventStaticDimensions)
+		maps.Copy(eventDimensions, dimensions)
+	}
+
+	return &Event{
+		Category:   eventCategory,
+		EventType:  getEventType(event),
+		Dimensions: eventDimensions,
+		Properties: eventProperties,
+		Timestamp:  event.EventTime.UnixMilli(),
+	}
+}
+
+// getEventType returns the configured event type of the lifecycle phase the request belongs to.
+func getEventType(event event_kit_api.EventRequestBody) string {
+	var eventType string
+	switch name := event.EventName; {
+	case slices.Contains(experimentStartedEvents, name):
+		eventType = config.Config.EventTypeExperimentStarted
+	case slices.Contains(experimentCompletedEvents, name):
+		eventType = config.Config.EventTypeExperimentCompleted
+	case slices.Contains(stepStartedEvents, name):
+		eventType = config.Config.EventTypeStepStarted
+	case slices.Contains(stepCompletedEvents, name):
+		eventType = config.Config.EventTypeStepCompleted
+	case slices.Contains(targetStartedEvents, name):
+		eventType = config.Config.EventTypeTargetStarted
+	case slices.Contains(targetCompletedEvents, name):
+		eventType = config.Config.EventTypeTargetCompleted
+	}
+	if eventType == "" {
+		return defaultEventType
+	}
+	return eventType
+}
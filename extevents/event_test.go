@@ -0,0 +1,169 @@
+// event_test.go
+package extevents
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+func TestOnExperimentStepCompleted(t *testing.T) {
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	ended := started.Add(90 * time.Second)
+	reason := "Detector check failed"
+	event := event_kit_api.EventRequestBody{
+		Environment: &event_kit_api.Environment{Name: "test"},
+		EventName:   "experiment.execution.step-failed",
+		EventTime:   ended,
+		ExperimentExecution: &event_kit_api.ExperimentExecution{
+			ExecutionId:   1,
+			ExperimentKey: "ADM-1",
+			Reason:        &reason,
+		},
+		ExperimentStepExecution: &event_kit_api.ExperimentStepExecution{
+			Id:          uuid.New(),
+			ExecutionId: 1,
+			State:       "failed",
+			Type:        event_kit_api.Wait,
+			StartedTime: &started,
+			EndedTime:   &ended,
+		},
+	}
+
+	result, err := onExperimentStepCompleted(event)
+	if err != nil {
+		t.Fatalf("onExperimentStepCompleted returned error: %v", err)
+	}
+	if result.Properties["step_state"] != "failed" {
+		t.Errorf("Expected step_state failed, got %s", result.Properties["step_state"])
+	}
+	if result.Properties["step_duration_ms"] != "90000" {
+		t.Errorf("Expected step_duration_ms 90000, got %s", result.Properties["step_duration_ms"])
+	}
+	if result.Properties["step_error"] != reason {
+		t.Errorf("Expected step_error %q, got %q", reason, result.Properties["step_error"])
+	}
+	if result.Timestamp != ended.UnixMilli() {
+		t.Errorf("Expected the timestamp of the event, got %d", result.Timestamp)
+	}
+}
+
+func TestOnExperimentStepCompleted_NoErrorOnSuccess(t *testing.T) {
+	for _, state := range []event_kit_api.ExperimentStepExecutionState{
+		event_kit_api.ExperimentStepExecutionStateCompleted,
+		event_kit_api.ExperimentStepExecutionStateCanceled,
+		event_kit_api.ExperimentStepExecutionStateSkipped,
+	} {
+		t.Run(string(state), func(t *testing.T) {
+			reason := "unrelated"
+			event := event_kit_api.EventRequestBody{
+				Environment:             &event_kit_api.Environment{Name: "test"},
+				ExperimentExecution:     &event_kit_api.ExperimentExecution{ExecutionId: 1, Reason: &reason},
+				ExperimentStepExecution: &event_kit_api.ExperimentStepExecution{Id: uuid.New(), State: state, Type: event_kit_api.Wait},
+			}
+
+			result, _ := onExperimentStepCompleted(event)
+			if _, ok := result.Properties["step_error"]; ok {
+				t.Errorf("Expected no step_error for a %s step", state)
+			}
+			if _, ok := result.Properties["step_duration_ms"]; ok {
+				t.Error("Expected no step_duration_ms without start and end time")
+			}
+		})
+	}
+}
+
+func TestGetTargetTags(t *testing.T) {
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		target event_kit_api.ExperimentStepTargetExecution
+		want   map[string]string
+	}{
+		{
+			name: "started target",
+			target: event_kit_api.ExperimentStepTargetExecution{
+				ExecutionId:   42,
+				ExperimentKey: "ADM-1",
+				State:         "running",
+				StartedTime:   &started,
+			},
+			want: map[string]string{
+				"execution_id":    "42",
+				"experiment_key":  "ADM-1",
+				"execution_state": "running",
+				"started_time":    "2025-01-01T10:00:00Z",
+			},
+		},
+		{
+			name: "completed target",
+			target: event_kit_api.ExperimentStepTargetExecution{
+				ExecutionId:   42,
+				ExperimentKey: "ADM-1",
+				State:         "completed",
+				StartedTime:   &started,
+				EndedTime:     new(started.Add(time.Minute)),
+			},
+			want: map[string]string{
+				"execution_id":    "42",
+				"experiment_key":  "ADM-1",
+				"execution_state": "completed",
+				"started_time":    "2025-01-01T10:00:00Z",
+				"ended_time":      "2025-01-01T10:01:00Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getTargetTags(tt.target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getTargetTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEvent_UsesConfiguredEventTypes(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.EventTypeExperimentStarted = "chaos.experiment.started"
+	config.Config.EventTypeTargetStarted = "chaos.attack.started"
+
+	tests := []struct {
+		eventName string
+		want      string
+	}{
+		{eventName: "experiment.execution.created", want: "chaos.experiment.started"},
+		{eventName: "experiment.execution.target-started", want: "chaos.attack.started"},
+		{eventName: "experiment.execution.step-started", want: defaultEventType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventName, func(t *testing.T) {
+			if got := newEvent(event_kit_api.EventRequestBody{EventName: tt.eventName}, nil, nil); got.EventType != tt.want || got.Category != category {
+				t.Errorf("newEvent() = %s/%s, want %s/%s", got.Category, got.EventType, category, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEvent_AddsStaticPropertiesAndDimensions(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.EventStaticProperties = map[string]string{"org": "payments", "source": "overridden"}
+	config.Config.EventStaticDimensions = map[string]string{"region": "eu-central-1"}
+
+	result := newEvent(event_kit_api.EventRequestBody{}, map[string]string{"source": "Steadybit"}, map[string]string{"host.name": "host-1"})
+
+	if want := map[string]string{"org": "payments", "source": "Steadybit"}; !reflect.DeepEqual(result.Properties, want) {
+		t.Errorf("Expected properties %v, got %v", want, result.Properties)
+	}
+	if want := map[string]string{"region": "eu-central-1", "host.name": "host-1"}; !reflect.DeepEqual(result.Dimensions, want) {
+		t.Errorf("Expected dimensions %v, got %v", want, result.Dimensions)
+	}
+	if len(config.Config.EventStaticDimensions) != 1 {
+		t.Error("Expected the static dimensions to be left untouched")
+	}
+}
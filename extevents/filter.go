@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"path"
+	"slices"
+
+	"github.com/steadybit/event-kit/go/event_kit_api"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+// eventFilter decides which experiments are forwarded to Splunk. An event is forwarded if it matches the include
+// rules, where an empty rule matches everything, and none of the exclude rules. Experiment keys and action ids are
+// matched as glob patterns, e.g. "SANDBOX-*".
+type eventFilter struct {
+	includeEnvironments   []string
+	excludeEnvironments   []string
+	includeTeams          []string
+	excludeTeams          []string
+	includeExperimentKeys []string
+	excludeExperimentKeys []string
+	includeActionIds      []string
+	excludeActionIds      []string
+}
+
+var filter eventFilter
+
+func newEventFilter() eventFilter {
+	return eventFilter{
+		includeEnvironments:   config.Config.EventFilterIncludeEnvironments,
+		excludeEnvironments:   config.Config.EventFilterExcludeEnvironments,
+		includeTeams:          config.Config.EventFilterIncludeTeams,
+		excludeTeams:          config.Config.EventFilterExcludeTeams,
+		includeExperimentKeys: config.Config.EventFilterIncludeExperimentKeys,
+		excludeExperimentKeys: config.Config.EventFilterExcludeExperimentKeys,
+		includeActionIds:      config.Config.EventFilterIncludeActionIds,
+		excludeActionIds:      config.Config.EventFilterExcludeActionIds,
+	}
+}
+
+func (f eventFilter) accepts(event event_kit_api.EventRequestBody) bool {
+	environment := ""
+	if event.Environment != nil {
+		environment = event.Environment.Name
+	}
+	if !matchesRule(environment, f.includeEnvironments, f.excludeEnvironments, equals) {
+		return false
+	}
+
+	team := ""
+	if event.Team != nil {
+		team = event.Team.Key
+	}
+	if !matchesRule(team, f.includeTeams, f.excludeTeams, equals) {
+		return false
+	}
+
+	if experimentKey, ok := getExperimentKey(event); ok && !matchesRule(experimentKey, f.includeExperimentKeys, f.excludeExperimentKeys, matchesPattern) {
+		return false
+	}
+
+	// Action ids only exist for steps and targets, the events of the experiment itself are always forwarded. Steps and
+	// targets with an unknown action id, e.g. of wait steps or of missed step events, never match the include rules.
+	if actionId, ok := getActionId(event); ok && !matchesRule(actionId, f.includeActionIds, f.excludeActionIds, matchesPattern) {
+		return false
+	}
+
+	return true
+}
+
+func matchesRule(value string, includes []string, excludes []string, matches func(value string, pattern string) bool) bool {
+	if len(includes) > 0 && !slices.ContainsFunc(includes, func(pattern string) bool { return matches(value, pattern) }) {
+		return false
+	}
+	return !slices.ContainsFunc(excludes, func(pattern string) bool { return matches(value, pattern) })
+}
+
+func equals(value string, expected string) bool {
+	return value == expected
+}
+
+func matchesPattern(value string, pattern string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+func getExperimentKey(event event_kit_api.EventRequestBody) (string, bool) {
+	switch {
+	case event.ExperimentExecution != nil:
+		return event.ExperimentExecution.ExperimentKey, true
+	case event.ExperimentStepExecution != nil:
+		return event.ExperimentStepExecution.ExperimentKey, true
+	case event.ExperimentStepTargetExecution != nil:
+		return event.ExperimentStepTargetExecution.ExperimentKey, true
+	}
+	return "", false
+}
+
+// getActionId returns the action id of step and target events, which is empty if it is unknown. The second result is
+// false for the events of the experiment itself.
+func getActionId(event event_kit_api.EventRequestBody) (string, bool) {
+	var step *event_kit_api.ExperimentStepExecution
+	switch {
+	case event.ExperimentStepTargetExecution != nil:
+		if stored, ok := stepExecutions.get(event.ExperimentStepTargetExecution.StepExecutionId.String()); ok {
+			step = &stored
+		}
+	case event.ExperimentStepExecution != nil:
+		step = event.ExperimentStepExecution
+	default:
+		return "", false
+	}
+	if step == nil || step.ActionId == nil {
+		return "", true
+	}
+	return *step.ActionId, true
+}
@@ -0,0 +1,83 @@
+// filter_test.go
+package extevents
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+)
+
+func TestEventFilter_Accepts(t *testing.T) {
+	stepExecutions = newStepExecutionStore(time.Hour, 10)
+	attack := event_kit_api.ExperimentStepExecution{Id: uuid.New(), ExecutionId: 1, ActionId: new("com.steadybit.extension_container.stress_cpu")}
+	stepExecutions.put(attack)
+
+	event := func(environment string, team string, experimentKey string) event_kit_api.EventRequestBody {
+		return event_kit_api.EventRequestBody{
+			Environment:         &event_kit_api.Environment{Name: environment},
+			Team:                &event_kit_api.Team{Key: team},
+			ExperimentExecution: &event_kit_api.ExperimentExecution{ExecutionId: 1, ExperimentKey: experimentKey},
+		}
+	}
+	targetEvent := event("prod", "ADM", "ADM-1")
+	targetEvent.ExperimentStepTargetExecution = &event_kit_api.ExperimentStepTargetExecution{StepExecutionId: attack.Id, ExperimentKey: "ADM-1"}
+	unknownTargetEvent := event("prod", "ADM", "ADM-1")
+	unknownTargetEvent.ExperimentStepTargetExecution = &event_kit_api.ExperimentStepTargetExecution{StepExecutionId: uuid.New(), ExperimentKey: "ADM-1"}
+	waitStepEvent := event("prod", "ADM", "ADM-1")
+	waitStepEvent.ExperimentStepExecution = &event_kit_api.ExperimentStepExecution{Id: uuid.New(), ExperimentKey: "ADM-1", Type: event_kit_api.Wait}
+
+	tests := []struct {
+		name     string
+		filter   eventFilter
+		event    event_kit_api.EventRequestBody
+		expected bool
+	}{
+		{name: "empty filter", filter: eventFilter{}, event: event("prod", "ADM", "ADM-1"), expected: true},
+		{name: "included environment", filter: eventFilter{includeEnvironments: []string{"prod"}}, event: event("prod", "ADM", "ADM-1"), expected: true},
+		{name: "not included environment", filter: eventFilter{includeEnvironments: []string{"prod"}}, event: event("sandbox", "ADM", "ADM-1"), expected: false},
+		{name: "excluded environment", filter: eventFilter{excludeEnvironments: []string{"sandbox"}}, event: event("sandbox", "ADM", "ADM-1"), expected: false},
+		{name: "excluded team", filter: eventFilter{excludeTeams: []string{"SMOKE"}}, event: event("prod", "SMOKE", "SMOKE-1"), expected: false},
+		{name: "exclude wins over include", filter: eventFilter{includeTeams: []string{"ADM"}, excludeExperimentKeys: []string{"ADM-*"}}, event: event("prod", "ADM", "ADM-1"), expected: false},
+		{name: "included experiment key pattern", filter: eventFilter{includeExperimentKeys: []string{"ADM-*"}}, event: event("prod", "ADM", "ADM-12"), expected: true},
+		{name: "not included experiment key pattern", filter: eventFilter{includeExperimentKeys: []string{"ADM-*"}}, event: event("prod", "OPS", "OPS-1"), expected: false},
+		{name: "action filter ignores experiment events", filter: eventFilter{includeActionIds: []string{"com.steadybit.extension_http.*"}}, event: event("prod", "ADM", "ADM-1"), expected: true},
+		{name: "excluded action of target", filter: eventFilter{excludeActionIds: []string{"com.steadybit.extension_container.*"}}, event: targetEvent, expected: false},
+		{name: "included action of target", filter: eventFilter{includeActionIds: []string{"com.steadybit.extension_container.stress_cpu"}}, event: targetEvent, expected: true},
+		{name: "unknown action of target not included", filter: eventFilter{includeActionIds: []string{"com.steadybit.extension_container.*"}}, event: unknownTargetEvent, expected: false},
+		{name: "unknown action of target not excluded", filter: eventFilter{excludeActionIds: []string{"com.steadybit.extension_container.*"}}, event: unknownTargetEvent, expected: true},
+		{name: "wait step not included", filter: eventFilter{includeActionIds: []string{"com.steadybit.extension_container.*"}}, event: waitStepEvent, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if accepted := tt.filter.accepts(tt.event); accepted != tt.expected {
+				t.Errorf("Expected accepts to return %v, got %v", tt.expected, accepted)
+			}
+		})
+	}
+}
+
+func TestHandle_SkipsFilteredEvents(t *testing.T) {
+	withDispatcherConfig(t, 1, time.Hour, 0)
+	sender := &recordingSender{}
+	sink := newQueuedSink("events", convertToCustomEvent, sender.send, nil)
+	previousSinks, previousFilter := sinks, filter
+	sinks, filter = []EventSink{sink}, eventFilter{excludeEnvironments: []string{"sandbox"}}
+	defer func() { sinks, filter = previousSinks, previousFilter }()
+
+	for _, environment := range []string{"sandbox", "prod"} {
+		body := `{"eventName":"experiment.execution.created","id":"` + uuid.NewString() + `","environment":{"name":"` + environment + `"},"tenant":{"key":"demo","name":"Demo"},"experimentExecution":{"executionId":1,"experimentKey":"ADM-1"}}`
+		recorder := httptest.NewRecorder()
+		handle(onExperiment)(recorder, httptest.NewRequest("POST", "/events/experiment-started", nil), []byte(body))
+		if recorder.Code != 200 {
+			t.Errorf("Expected status 200, got %d", recorder.Code)
+		}
+	}
+	sink.Shutdown(time.Second)
+
+	if sizes := sender.batchSizes(); len(sizes) != 1 {
+		t.Errorf("Expected only the prod event to be delivered, got %v", sizes)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+const hecSource = "steadybit"
+
+// HecRestyClient posts events to the HTTP Event Collector of Splunk Enterprise or Splunk Cloud Platform.
+var HecRestyClient *resty.Client
+
+// hecEvent is the envelope of an event sent to the HTTP Event Collector.
+type hecEvent struct {
+	Time       float64           `json:"time"`
+	Source     string            `json:"source"`
+	Sourcetype string            `json:"sourcetype,omitempty"`
+	Index      string            `json:"index,omitempty"`
+	Event      hecEventBody      `json:"event"`
+	Fields     map[string]string `json:"fields,omitempty"`
+}
+
+type hecEventBody struct {
+	Category   string            `json:"category"`
+	EventType  string            `json:"eventType"`
+	Properties map[string]string `json:"properties"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+}
+
+func toHecEvent(event *Event) hecEvent {
+	return hecEvent{
+		Time:       float64(event.Timestamp) / 1000,
+		Source:     hecSource,
+		Sourcetype: config.Config.HecSourcetype,
+		Index:      config.Config.HecIndex,
+		Event: hecEventBody{
+			Category:   event.Category,
+			EventType:  event.EventType,
+			Properties: event.Properties,
+			Dimensions: event.Dimensions,
+		},
+		// Dimensions are sent as indexed fields, so they can be searched just like the dimensions in Observability Cloud.
+		Fields: event.Dimensions,
+	}
+}
+
+// handlePostHecEvents posts the events to the HTTP Event Collector. The collector expects the events as concatenated
+// JSON objects rather than a JSON array.
+func handlePostHecEvents(ctx context.Context, client *resty.Client, events []*Event) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := encoder.Encode(toHecEvent(event)); err != nil {
+			return fmt.Errorf("failed to marshal events: %w", err)
+		}
+	}
+
+	res, err := client.R().
+		SetContext(ctx).
+		SetBody(body.Bytes()).
+		Post("/services/collector/event")
+
+	if err != nil {
+		return fmt.Errorf("failed to post events to the HTTP Event Collector: %w", err)
+	}
+
+	if !res.IsSuccess() {
+		return fmt.Errorf("splunk HTTP Event Collector responded with unexpected status code %d while posting events. Full response: %v", res.StatusCode(), res.String())
+	}
+
+	log.Debug().Msgf("Posted %d events to the Splunk HTTP Event Collector.", len(events))
+	return nil
+}
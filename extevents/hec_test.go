@@ -0,0 +1,94 @@
+// hec_test.go
+package extevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+func TestHandlePostHecEvents(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.HecIndex = "chaos"
+	config.Config.HecSourcetype = "steadybit:event"
+
+	var received []hecEvent
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/collector/event" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		decoder := json.NewDecoder(r.Body)
+		for {
+			var event hecEvent
+			if err := decoder.Decode(&event); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				t.Errorf("failed to decode events: %v", err)
+				break
+			}
+			received = append(received, event)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	events := []*Event{
+		{Category: category, EventType: "a", Timestamp: 1500, Properties: map[string]string{"exp_key": "ADM-1"}, Dimensions: map[string]string{"host.name": "host-1"}},
+		{Category: category, EventType: "b", Timestamp: 2000},
+	}
+	if err := handlePostHecEvents(context.Background(), resty.New().SetBaseURL(ts.URL), events); err != nil {
+		t.Fatalf("handlePostHecEvents returned error: %v", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 events in one request, got %d", len(received))
+	}
+	first := received[0]
+	if first.Time != 1.5 || first.Index != "chaos" || first.Sourcetype != "steadybit:event" || first.Source != hecSource {
+		t.Errorf("Unexpected envelope %+v", first)
+	}
+	if first.Event.EventType != "a" || first.Event.Properties["exp_key"] != "ADM-1" || first.Fields["host.name"] != "host-1" {
+		t.Errorf("Unexpected event %+v", first)
+	}
+}
+
+func TestHandlePostHecEvents_UnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	err := handlePostHecEvents(context.Background(), resty.New().SetBaseURL(ts.URL), []*Event{{EventType: "a"}})
+	if err == nil {
+		t.Error("Expected an error for a non-successful status code")
+	}
+}
+
+func TestNewSinks_HecOnlyDoesNotOpenTheSpool(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.ObservabilityEventsEnabled = false
+	config.Config.HecEnabled = true
+	config.Config.EventSpoolDirectory = filepath.Join(t.TempDir(), "spool")
+
+	sinks := newSinks()
+	defer shutdownSinks(sinks, time.Second)
+
+	if len(sinks) != 1 || sinks[0].Name() != "hec-events" {
+		t.Fatalf("Expected only the HEC sink, got %d sinks", len(sinks))
+	}
+	if _, err := os.Stat(config.Config.EventSpoolDirectory); !os.IsNotExist(err) {
+		t.Errorf("Expected the spool of the disabled Observability Cloud sink not to be opened, got %v", err)
+	}
+}
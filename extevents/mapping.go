@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// MultiValueSkip ignores attributes with multiple values, the next attribute of the rule is considered instead.
+	MultiValueSkip = "skip"
+	// MultiValueFirst uses the first value of attributes with multiple values.
+	MultiValueFirst = "first"
+	// MultiValueJoin joins all values of attributes with multiple values using the separator of the rule.
+	MultiValueJoin = "join"
+
+	defaultMultiValueSeparator = ","
+)
+
+//go:embed dimension_mapping.yaml
+var defaultDimensionMappingFile []byte
+
+// DimensionMapping declares how Steadybit target attributes are translated to the Splunk dimensions of target
+// events. The file format is YAML, which also accepts JSON.
+type DimensionMapping struct {
+	// IncludeDefaults applies the default rules before the rules of the mapping file.
+	IncludeDefaults bool            `yaml:"includeDefaults" json:"includeDefaults"`
+	Rules           []DimensionRule `yaml:"rules" json:"rules"`
+}
+
+// DimensionRule sets one Splunk dimension from the first present attribute, falling back to a static value.
+type DimensionRule struct {
+	Dimension  string   `yaml:"dimension" json:"dimension"`
+	Attributes []string `yaml:"attributes" json:"attributes"`
+	Value      string   `yaml:"value" json:"value"`
+	When       []string `yaml:"when" json:"when"`
+	MultiValue string   `yaml:"multiValue" json:"multiValue"`
+	Separator  string   `yaml:"separator" json:"separator"`
+}
+
+var dimensionMapping = mustParseDimensionMapping(defaultDimensionMappingFile)
+
+func mustParseDimensionMapping(data []byte) DimensionMapping {
+	mapping, err := parseDimensionMapping(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default dimension mapping: %v", err))
+	}
+	return mapping
+}
+
+// loadDimensionMapping reads the mapping file at the given path. The default mapping is returned if no path is given.
+func loadDimensionMapping(path string) (DimensionMapping, error) {
+	if path == "" {
+		return parseDimensionMapping(defaultDimensionMappingFile)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DimensionMapping{}, fmt.Errorf("failed to read dimension mapping file %s: %w", path, err)
+	}
+	mapping, err := parseDimensionMapping(data)
+	if err != nil {
+		return DimensionMapping{}, fmt.Errorf("invalid dimension mapping file %s: %w", path, err)
+	}
+	if mapping.IncludeDefaults {
+		defaults, err := parseDimensionMapping(defaultDimensionMappingFile)
+		if err != nil {
+			return DimensionMapping{}, err
+		}
+		mapping.Rules = append(defaults.Rules, mapping.Rules...)
+	}
+	return mapping, nil
+}
+
+func parseDimensionMapping(data []byte) (DimensionMapping, error) {
+	var mapping DimensionMapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return DimensionMapping{}, err
+	}
+
+	var errs []error
+	for i, rule := range mapping.Rules {
+		if rule.Dimension == "" {
+			errs = append(errs, fmt.Errorf("rule %d: dimension is missing", i+1))
+		}
+		if len(rule.Attributes) == 0 && rule.Value == "" {
+			errs = append(errs, fmt.Errorf("rule %d (%s): either attributes or a value is required", i+1, rule.Dimension))
+		}
+		switch rule.MultiValue {
+		case "", MultiValueSkip, MultiValueFirst, MultiValueJoin:
+		default:
+			errs = append(errs, fmt.Errorf("rule %d (%s): invalid multiValue '%s', expected one of %s, %s or %s", i+1, rule.Dimension, rule.MultiValue, MultiValueSkip, MultiValueFirst, MultiValueJoin))
+		}
+	}
+	return mapping, errors.Join(errs...)
+}
+
+// apply translates the attributes to dimensions. Later rules overwrite dimensions set by earlier rules.
+func (m DimensionMapping) apply(attributes map[string][]string) map[string]string {
+	dimensions := make(map[string]string)
+	for _, rule := range m.Rules {
+		if value, ok := rule.resolve(attributes); ok {
+			dimensions[rule.Dimension] = value
+		}
+	}
+	return dimensions
+}
+
+func (r DimensionRule) resolve(attributes map[string][]string) (string, bool) {
+	if len(r.When) > 0 && !hasAnyAttribute(attributes, r.When) {
+		return "", false
+	}
+
+	for _, attribute := range r.Attributes {
+		values := attributes[attribute]
+		switch {
+		case len(values) == 0:
+			continue
+		case len(values) == 1:
+			return values[0], true
+		case r.MultiValue == MultiValueFirst:
+			return values[0], true
+		case r.MultiValue == MultiValueJoin:
+			separator := r.Separator
+			if separator == "" {
+				separator = defaultMultiValueSeparator
+			}
+			return strings.Join(values, separator), true
+		}
+	}
+
+	if r.Value != "" {
+		return r.Value, true
+	}
+	return "", false
+}
+
+func hasAnyAttribute(attributes map[string][]string, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := attributes[key]; ok {
+			return true
+		}
+	}
+	return false
+}
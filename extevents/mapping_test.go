@@ -0,0 +1,206 @@
+// mapping_test.go
+package extevents
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultDimensionMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string][]string
+		want       map[string]string
+	}{
+		{
+			name: "kubernetes deployment",
+			attributes: map[string][]string{
+				"k8s.cluster-name":   {"prod"},
+				"k8s.namespace":      {"shop"},
+				"k8s.deployment":     {"checkout"},
+				"k8s.replicaset":     {"checkout-5d4f8"},
+				"k8s.pod.name":       {"checkout-5d4f8-x7k2p"},
+				"k8s.node.name":      {"node-1"},
+				"k8s.container.name": {"app"},
+			},
+			want: map[string]string{
+				"k8s.cluster.name":    "prod",
+				"k8s.namespace.name":  "shop",
+				"k8s.deployment.name": "checkout",
+				"k8s.replicaset.name": "checkout-5d4f8",
+				"k8s.pod.name":        "checkout-5d4f8-x7k2p",
+				"k8s.node.name":       "node-1",
+				"k8s.container.name":  "app",
+			},
+		},
+		{
+			name: "kubernetes statefulset",
+			attributes: map[string][]string{
+				"k8s.cluster-name": {"prod"},
+				"k8s.namespace":    {"shop"},
+				"k8s.statefulset":  {"postgres"},
+				"k8s.pod.name":     {"postgres-0"},
+			},
+			want: map[string]string{
+				"k8s.cluster.name":     "prod",
+				"k8s.namespace.name":   "shop",
+				"k8s.statefulset.name": "postgres",
+				"k8s.pod.name":         "postgres-0",
+			},
+		},
+		{
+			name: "kubernetes daemonset",
+			attributes: map[string][]string{
+				"k8s.cluster-name": {"prod"},
+				"k8s.namespace":    {"kube-system"},
+				"k8s.daemonset":    {"kube-proxy"},
+				"k8s.pod.name":     {"kube-proxy-abcde"},
+				"k8s.node.name":    {"node-2"},
+			},
+			want: map[string]string{
+				"k8s.cluster.name":   "prod",
+				"k8s.namespace.name": "kube-system",
+				"k8s.daemonset.name": "kube-proxy",
+				"k8s.pod.name":       "kube-proxy-abcde",
+				"k8s.node.name":      "node-2",
+			},
+		},
+		{
+			name:       "kubernetes attributes without cluster are ignored",
+			attributes: map[string][]string{"k8s.namespace": {"shop"}},
+			want:       map[string]string{},
+		},
+		{
+			name: "host name prefers the application hostname",
+			attributes: map[string][]string{
+				"container.host":       {"node-1"},
+				"application.hostname": {"app-1"},
+			},
+			want: map[string]string{"host.name": "app-1"},
+		},
+		{
+			name: "multi-valued attributes fall back to the next attribute",
+			attributes: map[string][]string{
+				"host.hostname":  {"a", "b"},
+				"container.host": {"node-1"},
+			},
+			want: map[string]string{"host.name": "node-1"},
+		},
+		{
+			name: "aws",
+			attributes: map[string][]string{
+				"aws.region":  {"eu-central-1"},
+				"aws.zone":    {"eu-central-1a"},
+				"aws.account": {"123"},
+			},
+			want: map[string]string{
+				"cloud.provider":          "aws",
+				"cloud.region":            "eu-central-1",
+				"cloud.availability_zone": "eu-central-1a",
+				"cloud.account.id":        "123",
+			},
+		},
+		{
+			name: "gcp",
+			attributes: map[string][]string{
+				"gcp.project.id": {"shop-prod"},
+				"gcp.region":     {"europe-west1"},
+				"gcp.zone":       {"europe-west1-b"},
+			},
+			want: map[string]string{
+				"cloud.provider":          "gcp",
+				"cloud.region":            "europe-west1",
+				"cloud.availability_zone": "europe-west1-b",
+				"cloud.account.id":        "shop-prod",
+			},
+		},
+		{
+			name: "gcp zone only",
+			attributes: map[string][]string{
+				"gcp.zone": {"europe-west1-b"},
+			},
+			want: map[string]string{
+				"cloud.provider":          "gcp",
+				"cloud.availability_zone": "europe-west1-b",
+			},
+		},
+		{
+			name: "azure",
+			attributes: map[string][]string{
+				"azure.subscription.id": {"0000-1111"},
+				"azure.location":        {"westeurope"},
+				"azure.zone":            {"westeurope-1"},
+			},
+			want: map[string]string{
+				"cloud.provider":          "azure",
+				"cloud.region":            "westeurope",
+				"cloud.availability_zone": "westeurope-1",
+				"cloud.account.id":        "0000-1111",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dimensionMapping.apply(tt.attributes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDimensionRule_MultiValue(t *testing.T) {
+	attributes := map[string][]string{"team": {"a", "b"}}
+	tests := []struct {
+		name string
+		rule DimensionRule
+		want string
+		ok   bool
+	}{
+		{name: "skip", rule: DimensionRule{Attributes: []string{"team"}}, ok: false},
+		{name: "first", rule: DimensionRule{Attributes: []string{"team"}, MultiValue: MultiValueFirst}, want: "a", ok: true},
+		{name: "join", rule: DimensionRule{Attributes: []string{"team"}, MultiValue: MultiValueJoin}, want: "a,b", ok: true},
+		{name: "join with separator", rule: DimensionRule{Attributes: []string{"team"}, MultiValue: MultiValueJoin, Separator: "|"}, want: "a|b", ok: true},
+		{name: "static fallback", rule: DimensionRule{Attributes: []string{"team"}, Value: "unknown"}, want: "unknown", ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.rule.resolve(attributes)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("resolve() = %q, %t, want %q, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLoadDimensionMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	content := `{"includeDefaults": true, "rules": [{"dimension": "service.name", "attributes": ["k8s.deployment", "application.name"]}]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := loadDimensionMapping(path)
+	if err != nil {
+		t.Fatalf("loadDimensionMapping returned error: %v", err)
+	}
+
+	got := mapping.apply(map[string][]string{"application.name": {"checkout"}, "host.hostname": {"host-1"}})
+	want := map[string]string{"service.name": "checkout", "host.name": "host-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("apply() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadDimensionMapping_InvalidRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	content := "rules:\n  - dimension: service.name\n  - attributes: [ team ]\n    multiValue: all\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadDimensionMapping(path); err == nil {
+		t.Error("Expected an error for invalid rules")
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+)
+
+const (
+	metricExperimentDuration   = "steadybit.experiment.duration"
+	metricExperimentExecutions = "steadybit.experiment.executions"
+	metricStepDuration         = "steadybit.step.duration"
+	metricStepExecutions       = "steadybit.step.executions"
+	metricTargetsAttacked      = "steadybit.targets.attacked"
+
+	metricTypeGauge   = "gauge"
+	metricTypeCounter = "counter"
+)
+
+// Datapoint is a SignalFx datapoint as accepted by the ingest API.
+type Datapoint struct {
+	Metric     string            `json:"metric"`
+	Value      float64           `json:"value"`
+	Dimensions map[string]string `json:"dimensions"`
+	Timestamp  int64             `json:"timestamp"`
+	// MetricType is either gauge or counter and decides the list the datapoint is posted in.
+	MetricType string `json:"-"`
+}
+
+// toDatapoints derives datapoints from the event: the duration and outcome of ended experiments and steps, and a count
+// of the attacked targets.
+func toDatapoints(event event_kit_api.EventRequestBody) []*Datapoint {
+	timestamp := event.EventTime.UnixMilli()
+	switch {
+	case event.ExperimentStepTargetExecution != nil:
+		target := event.ExperimentStepTargetExecution
+		if target.EndedTime == nil {
+			return nil
+		}
+		step, ok := stepExecutions.get(target.StepExecutionId.String())
+		if !ok || step.ActionKind == nil || *step.ActionKind != event_kit_api.Attack {
+			return nil
+		}
+		dimensions := getMetricDimensions(event, string(target.State))
+		if step.ActionId != nil {
+			dimensions["action_id"] = *step.ActionId
+		}
+		return []*Datapoint{
+			{Metric: metricTargetsAttacked, Value: 1, Dimensions: dimensions, Timestamp: timestamp, MetricType: metricTypeCounter},
+		}
+
+	case event.ExperimentStepExecution != nil:
+		step := event.ExperimentStepExecution
+		if step.EndedTime == nil {
+			return nil
+		}
+		dimensions := getMetricDimensions(event, string(step.State))
+		dimensions["step_type"] = string(step.Type)
+		if step.ActionId != nil {
+			dimensions["action_id"] = *step.ActionId
+		}
+		datapoints := []*Datapoint{
+			{Metric: metricStepExecutions, Value: 1, Dimensions: dimensions, Timestamp: timestamp, MetricType: metricTypeCounter},
+		}
+		if step.StartedTime != nil {
+			datapoints = append(datapoints, &Datapoint{Metric: metricStepDuration, Value: step.EndedTime.Sub(*step.StartedTime).Seconds(), Dimensions: dimensions, Timestamp: timestamp, MetricType: metricTypeGauge})
+		}
+		return datapoints
+
+	case event.ExperimentExecution != nil:
+		execution := event.ExperimentExecution
+		if execution.EndedTime == nil {
+			return nil
+		}
+		dimensions := getMetricDimensions(event, string(execution.State))
+		datapoints := []*Datapoint{
+			{Metric: metricExperimentExecutions, Value: 1, Dimensions: dimensions, Timestamp: timestamp, MetricType: metricTypeCounter},
+		}
+		if !execution.StartedTime.IsZero() {
+			datapoints = append(datapoints, &Datapoint{Metric: metricExperimentDuration, Value: execution.EndedTime.Sub(execution.StartedTime).Seconds(), Dimensions: dimensions, Timestamp: timestamp, MetricType: metricTypeGauge})
+		}
+		return datapoints
+	}
+	return nil
+}
+
+func getMetricDimensions(event event_kit_api.EventRequestBody, outcome string) map[string]string {
+	dimensions := map[string]string{
+		"source":  "Steadybit",
+		"outcome": strings.ToLower(outcome),
+	}
+	if event.ExperimentExecution != nil {
+		dimensions["experiment_key"] = event.ExperimentExecution.ExperimentKey
+	}
+	if event.Team != nil {
+		dimensions["team"] = event.Team.Key
+	}
+	if event.Environment != nil {
+		dimensions["environment"] = event.Environment.Name
+	}
+	return dimensions
+}
+
+func handlePostDatapoints(ctx context.Context, client *resty.Client, datapoints []*Datapoint) error {
+	body := make(map[string][]*Datapoint)
+	for _, datapoint := range datapoints {
+		body[datapoint.MetricType] = append(body[datapoint.MetricType], datapoint)
+	}
+	datapointBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal datapoints: %w", err)
+	}
+
+	res, err := client.R().
+		SetContext(ctx).
+		SetBody(datapointBytes).
+		Post("/v2/datapoint")
+
+	if err != nil {
+		return fmt.Errorf("failed to post datapoints: %w", err)
+	}
+
+	if !res.IsSuccess() {
+		return fmt.Errorf("splunk ingest API responded with unexpected status code %d while posting datapoints. Full response: %v", res.StatusCode(), res.String())
+	}
+
+	log.Debug().Msgf("Posted %d datapoints to Splunk.", len(datapoints))
+	return nil
+}
@@ -0,0 +1,102 @@
+// metrics_test.go
+package extevents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+)
+
+func TestToDatapoints_Experiment(t *testing.T) {
+	experimentEvent, _, _ := traceTestEvents()
+	experimentEvent.Team = &event_kit_api.Team{Key: "shop"}
+
+	datapoints := toDatapoints(experimentEvent)
+
+	if len(datapoints) != 2 {
+		t.Fatalf("Expected an execution counter and a duration gauge, got %d datapoints", len(datapoints))
+	}
+	counter, gauge := datapoints[0], datapoints[1]
+	if counter.Metric != metricExperimentExecutions || counter.MetricType != metricTypeCounter || counter.Value != 1 {
+		t.Errorf("Unexpected counter %+v", counter)
+	}
+	if gauge.Metric != metricExperimentDuration || gauge.MetricType != metricTypeGauge || gauge.Value != 60 {
+		t.Errorf("Unexpected gauge %+v", gauge)
+	}
+	want := map[string]string{"source": "Steadybit", "outcome": "failed", "experiment_key": "ADM-1", "team": "shop", "environment": "test"}
+	for key, value := range want {
+		if counter.Dimensions[key] != value {
+			t.Errorf("Expected dimension %s=%s, got %s", key, value, counter.Dimensions[key])
+		}
+	}
+}
+
+func TestToDatapoints_Step(t *testing.T) {
+	_, stepEvent, _ := traceTestEvents()
+
+	datapoints := toDatapoints(stepEvent)
+
+	if len(datapoints) != 2 || datapoints[1].Metric != metricStepDuration || datapoints[1].Value != 60 {
+		t.Fatalf("Expected a step duration of 60 seconds, got %v", datapoints)
+	}
+	if datapoints[0].Dimensions["outcome"] != "completed" || datapoints[0].Dimensions["action_id"] != "com.steadybit.extension_container.network_delay" {
+		t.Errorf("Unexpected dimensions %v", datapoints[0].Dimensions)
+	}
+}
+
+func TestToDatapoints_CountsAttackedTargetsOnly(t *testing.T) {
+	stepExecutions = newStepExecutionStore(time.Hour, 10)
+	_, stepEvent, targetEvent := traceTestEvents()
+
+	if datapoints := toDatapoints(targetEvent); len(datapoints) != 0 {
+		t.Errorf("Expected no datapoints for targets of unknown steps, got %v", datapoints)
+	}
+
+	attack := event_kit_api.Attack
+	stepEvent.ExperimentStepExecution.ActionKind = &attack
+	stepExecutions.put(*stepEvent.ExperimentStepExecution)
+
+	datapoints := toDatapoints(targetEvent)
+	if len(datapoints) != 1 || datapoints[0].Metric != metricTargetsAttacked {
+		t.Errorf("Expected an attacked target, got %v", datapoints)
+	}
+
+	targetEvent.ExperimentStepTargetExecution.StepExecutionId = uuid.New()
+	stepExecutions.put(event_kit_api.ExperimentStepExecution{Id: targetEvent.ExperimentStepTargetExecution.StepExecutionId, Type: event_kit_api.Wait})
+	if datapoints := toDatapoints(targetEvent); len(datapoints) != 0 {
+		t.Errorf("Expected no datapoints for targets of non-attack steps, got %v", datapoints)
+	}
+}
+
+func TestHandlePostDatapoints_GroupsByMetricType(t *testing.T) {
+	var received map[string][]Datapoint
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/datapoint" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode datapoints: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	err := handlePostDatapoints(context.Background(), resty.New().SetBaseURL(ts.URL), []*Datapoint{
+		{Metric: metricExperimentExecutions, Value: 1, MetricType: metricTypeCounter},
+		{Metric: metricExperimentDuration, Value: 60, MetricType: metricTypeGauge},
+		{Metric: metricStepDuration, Value: 30, MetricType: metricTypeGauge},
+	})
+	if err != nil {
+		t.Fatalf("handlePostDatapoints returned error: %v", err)
+	}
+	if len(received["counter"]) != 1 || len(received["gauge"]) != 2 {
+		t.Errorf("Expected 1 counter and 2 gauges, got %v", received)
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+)
+
+const (
+	// RedactionModeHash replaces redacted values with a hash, so equal values can still be correlated.
+	RedactionModeHash = "hash"
+	// RedactionModeDrop removes redacted properties and dimensions.
+	RedactionModeDrop = "drop"
+)
+
+// redaction removes sensitive values from the properties and dimensions before they are handed to any sink. A value
+// is redacted if its key matches one of the key patterns or the value matches one of the value expressions.
+type redaction struct {
+	keys   []string
+	values []*regexp.Regexp
+	mode   string
+}
+
+var eventRedaction redaction
+
+func newRedaction(keys []string, values []string, mode string) (redaction, error) {
+	if mode == "" {
+		mode = RedactionModeHash
+	}
+	if mode != RedactionModeHash && mode != RedactionModeDrop {
+		return redaction{}, fmt.Errorf("invalid redaction mode '%s', expected %s or %s", mode, RedactionModeHash, RedactionModeDrop)
+	}
+
+	for _, key := range keys {
+		if _, err := path.Match(key, ""); err != nil {
+			return redaction{}, fmt.Errorf("invalid redaction key pattern '%s': %w", key, err)
+		}
+	}
+
+	expressions := make([]*regexp.Regexp, 0, len(values))
+	for _, value := range values {
+		expression, err := regexp.Compile(value)
+		if err != nil {
+			return redaction{}, fmt.Errorf("invalid redaction value expression '%s': %w", value, err)
+		}
+		expressions = append(expressions, expression)
+	}
+
+	return redaction{keys: keys, values: expressions, mode: mode}, nil
+}
+
+func (r redaction) enabled() bool {
+	return len(r.keys) > 0 || len(r.values) > 0
+}
+
+// apply returns a copy of the values with the sensitive values redacted.
+func (r redaction) apply(values map[string]string) map[string]string {
+	if !r.enabled() || values == nil {
+		return values
+	}
+
+	redacted := make(map[string]string, len(values))
+	for key, value := range values {
+		if !r.matches(key, value) {
+			redacted[key] = value
+		} else if r.mode == RedactionModeHash {
+			redacted[key] = hashValue(value)
+		}
+	}
+	return redacted
+}
+
+// applyToValue returns the value redacted as if it was stored under the given key, dropped values are returned empty.
+func (r redaction) applyToValue(key string, value string) string {
+	if !r.matches(key, value) {
+		return value
+	} else if r.mode == RedactionModeHash {
+		return hashValue(value)
+	}
+	return ""
+}
+
+func (r redaction) applyToEvent(event *Event) *Event {
+	if !r.enabled() || event == nil {
+		return event
+	}
+	redacted := *event
+	redacted.Properties = r.apply(event.Properties)
+	redacted.Dimensions = r.apply(event.Dimensions)
+	return &redacted
+}
+
+func (r redaction) matches(key string, value string) bool {
+	return slices.ContainsFunc(r.keys, func(pattern string) bool { return matchesPattern(key, pattern) }) ||
+		slices.ContainsFunc(r.values, func(expression *regexp.Regexp) bool { return expression.MatchString(value) })
+}
+
+func hashValue(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(hash[:8])
+}
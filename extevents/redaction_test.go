@@ -0,0 +1,104 @@
+// redaction_test.go
+package extevents
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedaction_Apply(t *testing.T) {
+	values := map[string]string{
+		"exp_name":     "Latency for ACME Corp",
+		"exp_key":      "ADM-1",
+		"k8s.pod.name": "checkout-customer-4711",
+		"env":          "prod",
+	}
+	tests := []struct {
+		name   string
+		keys   []string
+		values []string
+		mode   string
+		want   map[string]string
+	}{
+		{
+			name: "disabled",
+			want: values,
+		},
+		{
+			name: "hash by key pattern",
+			keys: []string{"exp_name", "k8s.*"},
+			want: map[string]string{
+				"exp_name":     hashValue("Latency for ACME Corp"),
+				"exp_key":      "ADM-1",
+				"k8s.pod.name": hashValue("checkout-customer-4711"),
+				"env":          "prod",
+			},
+		},
+		{
+			name:   "drop by value expression",
+			values: []string{`customer-\d+`, `(?i)acme`},
+			mode:   RedactionModeDrop,
+			want: map[string]string{
+				"exp_key": "ADM-1",
+				"env":     "prod",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newRedaction(tt.keys, tt.values, tt.mode)
+			if err != nil {
+				t.Fatalf("newRedaction() returned error: %v", err)
+			}
+			if got := r.apply(values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if values["exp_name"] != "Latency for ACME Corp" {
+		t.Error("Expected the original values to be left untouched")
+	}
+}
+
+func TestNewRedaction_RejectsInvalidConfiguration(t *testing.T) {
+	if _, err := newRedaction(nil, []string{"("}, RedactionModeHash); err == nil {
+		t.Error("Expected an error for an invalid value expression")
+	}
+	if _, err := newRedaction([]string{"["}, nil, RedactionModeHash); err == nil {
+		t.Error("Expected an error for an invalid key pattern")
+	}
+	if _, err := newRedaction(nil, nil, "mask"); err == nil {
+		t.Error("Expected an error for an invalid mode")
+	}
+}
+
+func TestRedaction_AppliesToSpans(t *testing.T) {
+	previous := eventRedaction
+	defer func() { eventRedaction = previous }()
+	eventRedaction, _ = newRedaction([]string{"exp_name"}, nil, RedactionModeDrop)
+
+	experiment, _, _ := traceTestEvents()
+	span := toSpan(experiment)
+
+	if span.Name != "ADM-1" {
+		t.Errorf("Expected the span name without the experiment name, got %s", span.Name)
+	}
+	for _, attribute := range span.Attributes {
+		if attribute.Key == "exp_name" {
+			t.Errorf("Expected exp_name to be dropped, got %v", attribute.Value)
+		}
+	}
+}
+
+func TestRedaction_AppliesToSpanStatus(t *testing.T) {
+	previous := eventRedaction
+	defer func() { eventRedaction = previous }()
+	eventRedaction, _ = newRedaction(nil, []string{"Attack"}, RedactionModeHash)
+
+	experiment, _, _ := traceTestEvents()
+	span := toSpan(experiment)
+
+	if span.Status.Message != hashValue("Attack failed") {
+		t.Errorf("Expected the hashed reason as status message, got %s", span.Status.Message)
+	}
+}
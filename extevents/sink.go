@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+	"github.com/steadybit/extension-splunk/config"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// EventSink delivers the experiment lifecycle to one Splunk product or API.
+type EventSink interface {
+	// Name identifies the sink in logs and statistics.
+	Name() string
+	// Accept queues the items derived from the event for delivery and must not block. event is the custom event built
+	// by the event handler, it is nil if the handler didn't build one.
+	Accept(request event_kit_api.EventRequestBody, event *Event)
+	// Shutdown stops accepting events and waits for the queued items to be delivered, at most for the given timeout.
+	Shutdown(timeout time.Duration)
+	Stats() SinkStats
+}
+
+// queuedSink converts the events into the items of a Splunk API and delivers them through its own dispatcher, so a
+// slow or failing API doesn't delay the delivery to the other sinks.
+type queuedSink[T any] struct {
+	name       string
+	convert    func(request event_kit_api.EventRequestBody, event *Event) []T
+	dispatcher *dispatcher[T]
+}
+
+func newQueuedSink[T any](name string, convert func(request event_kit_api.EventRequestBody, event *Event) []T, send sendFunc[T], spool *spool[T]) *queuedSink[T] {
+	sink := &queuedSink[T]{name: name, convert: convert, dispatcher: newDispatcher(name, send, spool)}
+	sink.dispatcher.start()
+	return sink
+}
+
+func (s *queuedSink[T]) Name() string {
+	return s.name
+}
+
+// Accept queues the items derived from the event for delivery. event is the custom event built by the event handler,
+// it is nil if the handler didn't build one.
+func (s *queuedSink[T]) Accept(request event_kit_api.EventRequestBody, event *Event) {
+	for _, item := range s.convert(request, event) {
+		s.dispatcher.enqueue(item)
+	}
+}
+
+// Shutdown stops accepting events and waits for the queued items to be delivered, at most for the given timeout.
+func (s *queuedSink[T]) Shutdown(timeout time.Duration) {
+	s.dispatcher.shutdown(timeout)
+}
+
+func (s *queuedSink[T]) Stats() SinkStats {
+	return SinkStats{
+		Name:         s.name,
+		QueuedItems:  len(s.dispatcher.queue),
+		DroppedItems: s.dispatcher.dropped.Load(),
+	}
+}
+
+var sinks []EventSink
+
+// newSinks creates the sinks enabled in the configuration.
+func newSinks() []EventSink {
+	var result []EventSink
+	if config.Config.ObservabilityEventsEnabled {
+		result = append(result, newQueuedSink("observability-events", convertToCustomEvent, func(ctx context.Context, events []*Event) error {
+			return handlePostEvent(ctx, RestyClient, events)
+		}, openEventSpool()))
+	}
+	if config.Config.HecEnabled {
+		result = append(result, newQueuedSink("hec-events", convertToCustomEvent, func(ctx context.Context, events []*Event) error {
+			return handlePostHecEvents(ctx, HecRestyClient, events)
+		}, nil))
+	}
+	if config.Config.TraceExportEnabled {
+		result = append(result, newQueuedSink("traces", convertToSpans, func(ctx context.Context, spans []*tracepb.Span) error {
+			return handlePostSpans(ctx, RestyClient, spans)
+		}, nil))
+	}
+	if config.Config.MetricExportEnabled {
+		result = append(result, newQueuedSink("metrics", convertToDatapoints, func(ctx context.Context, datapoints []*Datapoint) error {
+			return handlePostDatapoints(ctx, RestyClient, datapoints)
+		}, nil))
+	}
+	return result
+}
+
+// openEventSpool opens the spool of the custom events sent to Splunk Observability Cloud. It is only opened for an
+// enabled sink, as opening it replays the batches left over from earlier runs.
+func openEventSpool() *spool[*Event] {
+	eventSpool, err := newSpool[*Event](config.Config.EventSpoolDirectory, config.Config.EventSpoolMaxBytes)
+	if err != nil {
+		log.Err(err).Msg("Failed to open the event spool, undelivered events will be dropped.")
+		return nil
+	}
+	return eventSpool
+}
+
+func convertToCustomEvent(_ event_kit_api.EventRequestBody, event *Event) []*Event {
+	if event == nil {
+		return nil
+	}
+	return []*Event{event}
+}
+
+func convertToSpans(request event_kit_api.EventRequestBody, _ *Event) []*tracepb.Span {
+	if span := toSpan(request); span != nil {
+		return []*tracepb.Span{span}
+	}
+	return nil
+}
+
+func convertToDatapoints(request event_kit_api.EventRequestBody, _ *Event) []*Datapoint {
+	datapoints := toDatapoints(request)
+	for _, datapoint := range datapoints {
+		datapoint.Dimensions = eventRedaction.apply(datapoint.Dimensions)
+	}
+	return datapoints
+}
+
+// dispatch fans the event out to all sinks. A sink failing to accept the event doesn't affect the other sinks.
+func dispatch(sinks []EventSink, request event_kit_api.EventRequestBody, event *Event) {
+	for _, sink := range sinks {
+		dispatchTo(sink, request, event)
+	}
+}
+
+func dispatchTo(sink EventSink, request event_kit_api.EventRequestBody, event *Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Msgf("Sink %s failed to accept event %s: %v", sink.Name(), request.EventName, r)
+		}
+	}()
+	sink.Accept(request, event)
+}
+
+// shutdownSinks drains all sinks in parallel, so the timeout applies to each sink rather than adding up.
+func shutdownSinks(sinks []EventSink, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, sink := range sinks {
+		wg.Go(func() {
+			sink.Shutdown(timeout)
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+// sink_test.go
+package extevents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steadybit/event-kit/go/event_kit_api"
+)
+
+type panickingSink struct{}
+
+func (panickingSink) Name() string { return "panicking" }
+func (panickingSink) Accept(event_kit_api.EventRequestBody, *Event) {
+	panic("forced panic")
+}
+func (panickingSink) Shutdown(time.Duration) {}
+func (panickingSink) Stats() SinkStats       { return SinkStats{Name: "panicking"} }
+
+func TestDispatch_IsolatesFailingSinks(t *testing.T) {
+	withDispatcherConfig(t, 1, time.Hour, 0)
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+	stuck := newQueuedSink("stuck", convertToCustomEvent, func(ctx context.Context, events []*Event) error {
+		<-blocked
+		return nil
+	}, nil)
+	healthySender := &recordingSender{}
+	healthy := newQueuedSink("healthy", convertToCustomEvent, healthySender.send, nil)
+	sinks := []EventSink{panickingSink{}, stuck, healthy}
+
+	for i := 0; i < 3; i++ {
+		dispatch(sinks, event_kit_api.EventRequestBody{}, &Event{EventType: "test"})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(healthySender.batchSizes()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sizes := healthySender.batchSizes(); len(sizes) != 3 {
+		t.Errorf("Expected the healthy sink to deliver all events, got %v", sizes)
+	}
+	healthy.Shutdown(time.Second)
+}
+
+func TestDispatch_SkipsSinksWithoutItems(t *testing.T) {
+	withDispatcherConfig(t, 1, time.Hour, 0)
+	sender := &recordingSender{}
+	sink := newQueuedSink("events", convertToCustomEvent, sender.send, nil)
+
+	dispatch([]EventSink{sink}, event_kit_api.EventRequestBody{}, nil)
+	sink.Shutdown(time.Second)
+
+	if sender.calls != 0 {
+		t.Errorf("Expected no delivery without a custom event, got %d calls", sender.calls)
+	}
+}
+
+func TestQueuedSink_Stats(t *testing.T) {
+	withDispatcherConfig(t, 100, time.Hour, 0)
+	sink := newQueuedSink("events", convertToCustomEvent, (&recordingSender{failures: 1}).send, nil)
+
+	sink.Accept(event_kit_api.EventRequestBody{}, &Event{EventType: "test"})
+	sink.Shutdown(time.Second)
+
+	if stats := sink.Stats(); stats.Name != "events" || stats.DroppedItems != 1 {
+		t.Errorf("Expected 1 dropped item, got %+v", stats)
+	}
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultSpoolMaxBytes = 50 * 1024 * 1024
+	spoolFileExtension   = ".json"
+)
+
+// spool persists batches which could not be delivered to Splunk. Each batch is stored as a separate JSON file, named
+// by an increasing sequence number, so the batches can be replayed in the order they were spooled.
+type spool[T any] struct {
+	directory string
+	maxBytes  int64
+
+	mu    sync.Mutex
+	seq   uint64
+	bytes int64
+	files int
+}
+
+// newSpool opens the spool in the given directory and picks up the batches left over by a previous run. It returns
+// nil if no directory is configured.
+func newSpool[T any](directory string, maxBytes int64) (*spool[T], error) {
+	if directory == "" {
+		return nil, nil
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultSpoolMaxBytes
+	}
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory %s: %w", directory, err)
+	}
+
+	s := &spool[T]{directory: directory, maxBytes: maxBytes}
+	names, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(directory, name))
+		if err != nil {
+			continue
+		}
+		s.bytes += info.Size()
+		s.files++
+		if seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolFileExtension), 10, 64); err == nil && seq > s.seq {
+			s.seq = seq
+		}
+	}
+	stats.spooledBatches.Store(int64(s.files))
+	if s.files > 0 {
+		log.Info().Msgf("Found %d undelivered batches of events in spool directory %s.", s.files, directory)
+	}
+	return s, nil
+}
+
+// list returns the names of the spooled batches in the order they were spooled.
+func (s *spool[T]) list() ([]string, error) {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory %s: %w", s.directory, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), spoolFileExtension) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *spool[T]) pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.files > 0
+}
+
+// store persists the batch. The batch is dropped if it would exceed the size cap of the spool.
+func (s *spool[T]) store(batch []T) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bytes+int64(len(data)) > s.maxBytes {
+		return fmt.Errorf("spool directory %s exceeds the limit of %d bytes", s.directory, s.maxBytes)
+	}
+
+	tmp, err := os.CreateTemp(s.directory, "batch-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create spool file: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write spool file: %w", err)
+	}
+
+	s.seq++
+	if err := os.Rename(tmp.Name(), filepath.Join(s.directory, fmt.Sprintf("%020d%s", s.seq, spoolFileExtension))); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write spool file: %w", err)
+	}
+	s.bytes += int64(len(data))
+	s.files++
+	stats.spooledBatches.Store(int64(s.files))
+	return nil
+}
+
+// replay delivers the spooled batches in order and removes them once delivered. It stops at the first batch which
+// can't be delivered and reports whether the spool was fully replayed.
+func (s *spool[T]) replay(send sendFunc[T]) bool {
+	names, err := s.list()
+	if err != nil {
+		log.Err(err).Msg("Failed to replay spooled events.")
+		return false
+	}
+
+	for _, name := range names {
+		path := filepath.Join(s.directory, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Err(err).Msgf("Failed to read spooled events from %s.", path)
+			return false
+		}
+
+		var batch []T
+		if err := json.Unmarshal(data, &batch); err != nil {
+			log.Err(err).Msgf("Discarding corrupt spool file %s.", path)
+			s.remove(path, int64(len(data)))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+		err = send(ctx, batch)
+		cancel()
+		if err != nil {
+			log.Debug().Err(err).Msgf("Failed to replay %d spooled events, keeping them for later.", len(batch))
+			return false
+		}
+		log.Debug().Msgf("Replayed %d spooled events.", len(batch))
+		s.remove(path, int64(len(data)))
+	}
+	return true
+}
+
+func (s *spool[T]) remove(path string, size int64) {
+	if err := os.Remove(path); err != nil {
+		log.Err(err).Msgf("Failed to remove spool file %s.", path)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bytes -= size
+	s.files--
+	stats.spooledBatches.Store(int64(s.files))
+}
@@ -0,0 +1,130 @@
+// spool_test.go
+package extevents
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSpool_ReplaysInOrder(t *testing.T) {
+	s, err := newSpool[*Event](t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("newSpool returned error: %v", err)
+	}
+
+	for _, eventType := range []string{"first", "second", "third"} {
+		if err := s.store([]*Event{{EventType: eventType}}); err != nil {
+			t.Fatalf("store returned error: %v", err)
+		}
+	}
+
+	var replayed []string
+	ok := s.replay(func(_ context.Context, events []*Event) error {
+		replayed = append(replayed, events[0].EventType)
+		return nil
+	})
+
+	if !ok {
+		t.Error("Expected the spool to be fully replayed")
+	}
+	if len(replayed) != 3 || replayed[0] != "first" || replayed[1] != "second" || replayed[2] != "third" {
+		t.Errorf("Expected the batches in spool order, got %v", replayed)
+	}
+	if s.pending() {
+		t.Error("Expected no pending batches after the replay")
+	}
+}
+
+func TestSpool_StopsReplayOnFailure(t *testing.T) {
+	s, _ := newSpool[*Event](t.TempDir(), 0)
+	_ = s.store([]*Event{{EventType: "first"}})
+	_ = s.store([]*Event{{EventType: "second"}})
+
+	calls := 0
+	ok := s.replay(func(_ context.Context, events []*Event) error {
+		calls++
+		return errors.New("forced error")
+	})
+
+	if ok || calls != 1 {
+		t.Errorf("Expected the replay to stop after the first failure, got ok=%t after %d calls", ok, calls)
+	}
+	if !s.pending() {
+		t.Error("Expected the batches to be kept for a later replay")
+	}
+}
+
+func TestSpool_PicksUpBatchesOfPreviousRun(t *testing.T) {
+	dir := t.TempDir()
+	previous, _ := newSpool[*Event](dir, 0)
+	_ = previous.store([]*Event{{EventType: "first"}})
+
+	s, err := newSpool[*Event](dir, 0)
+	if err != nil {
+		t.Fatalf("newSpool returned error: %v", err)
+	}
+	_ = s.store([]*Event{{EventType: "second"}})
+
+	var replayed []string
+	s.replay(func(_ context.Context, events []*Event) error {
+		replayed = append(replayed, events[0].EventType)
+		return nil
+	})
+	if len(replayed) != 2 || replayed[0] != "first" || replayed[1] != "second" {
+		t.Errorf("Expected the batches of the previous run first, got %v", replayed)
+	}
+}
+
+func TestSpool_RejectsBatchesBeyondLimit(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := newSpool[*Event](dir, 100)
+
+	if err := s.store([]*Event{{EventType: "small"}}); err != nil {
+		t.Fatalf("Expected the first batch to fit into the spool, got %v", err)
+	}
+	if err := s.store([]*Event{{EventType: "small"}}); err == nil {
+		t.Error("Expected the second batch to exceed the limit")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 spool file, got %d", len(entries))
+	}
+}
+
+func TestDispatcher_SpoolsUndeliveredBatches(t *testing.T) {
+	withDispatcherConfig(t, 1, time.Hour, 0)
+	s, _ := newSpool[*Event](t.TempDir(), 0)
+	sender := &recordingSender{failures: 1}
+	d := newDispatcher("events", sender.send, s)
+
+	// The first batch fails and is spooled, the second is spooled as well to keep the order.
+	d.flush([]*Event{{EventType: "first"}}, false)
+	d.flush([]*Event{{EventType: "second"}}, false)
+	if sender.calls != 1 || !s.pending() {
+		t.Fatalf("Expected both batches to be spooled after one delivery attempt, got %d calls", sender.calls)
+	}
+
+	d.flush([]*Event{{EventType: "third"}}, true)
+	if len(sender.batches) != 3 || sender.batches[0][0].EventType != "first" || sender.batches[2][0].EventType != "third" {
+		t.Errorf("Expected the spooled batches to be delivered before the new one, got %v", sender.batches)
+	}
+	if s.pending() {
+		t.Error("Expected no pending batches after the replay")
+	}
+}
+
+func TestDispatcher_CountsDroppedEvents(t *testing.T) {
+	withDispatcherConfig(t, 1, time.Hour, 0)
+	before := GetDeliveryStats().DroppedEvents
+	d := newDispatcher("events", (&recordingSender{failures: 1}).send, nil)
+
+	d.flush([]*Event{{EventType: "first"}, {EventType: "second"}}, true)
+
+	if dropped := GetDeliveryStats().DroppedEvents - before; dropped != 2 {
+		t.Errorf("Expected 2 dropped events, got %d", dropped)
+	}
+}
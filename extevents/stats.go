@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import "sync/atomic"
+
+// DeliveryStats summarizes the delivery of events to Splunk and the bookkeeping of step executions since the start
+// of the extension.
+type DeliveryStats struct {
+	// DroppedEvents counts the events, spans and datapoints which were neither delivered nor spooled.
+	DroppedEvents int64 `json:"droppedEvents"`
+	// SpooledBatches is the number of batches currently awaiting replay from the spool.
+	SpooledBatches int64 `json:"spooledBatches"`
+	// StoredStepExecutions is the number of started steps currently remembered to enrich target events.
+	StoredStepExecutions int64 `json:"storedStepExecutions"`
+	// ExpiredStepExecutions counts the steps evicted because their ttl expired before the experiment completed.
+	ExpiredStepExecutions int64 `json:"expiredStepExecutions"`
+	// EvictedStepExecutions counts the steps evicted because the maximum number of stored steps was exceeded.
+	EvictedStepExecutions int64 `json:"evictedStepExecutions"`
+	// UnknownStepExecutions counts the target events for which no step information was available.
+	UnknownStepExecutions int64 `json:"unknownStepExecutions"`
+	// Sinks reports the delivery of each enabled sink.
+	Sinks []SinkStats `json:"sinks"`
+}
+
+// SinkStats summarizes the delivery of one sink.
+type SinkStats struct {
+	Name string `json:"name"`
+	// QueuedItems is the number of items currently waiting for delivery.
+	QueuedItems int `json:"queuedItems"`
+	// DroppedItems counts the items of this sink which were neither delivered nor spooled.
+	DroppedItems int64 `json:"droppedItems"`
+}
+
+var stats struct {
+	droppedEvents  atomic.Int64
+	spooledBatches atomic.Int64
+
+	storedStepExecutions  atomic.Int64
+	expiredStepExecutions atomic.Int64
+	evictedStepExecutions atomic.Int64
+	unknownStepExecutions atomic.Int64
+}
+
+func GetDeliveryStats() DeliveryStats {
+	sinkStats := make([]SinkStats, 0, len(sinks))
+	for _, sink := range sinks {
+		sinkStats = append(sinkStats, sink.Stats())
+	}
+	return DeliveryStats{
+		DroppedEvents:  stats.droppedEvents.Load(),
+		SpooledBatches: stats.spooledBatches.Load(),
+
+		StoredStepExecutions:  stats.storedStepExecutions.Load(),
+		ExpiredStepExecutions: stats.expiredStepExecutions.Load(),
+		EvictedStepExecutions: stats.evictedStepExecutions.Load(),
+		UnknownStepExecutions: stats.unknownStepExecutions.Load(),
+		Sinks:                 sinkStats,
+	}
+}
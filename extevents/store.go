@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/steadybit/event-kit/go/event_kit_api"
+)
+
+const (
+	defaultStepExecutionTtl        = 24 * time.Hour
+	defaultStepExecutionMaxEntries = 10000
+)
+
+// stepExecutionStore remembers the started steps, so the target events can be enriched with the step information.
+// Entries are evicted once the experiment completed, after the ttl expired or when the store exceeds its maximum
+// size, whichever comes first. The oldest entries are evicted first.
+type stepExecutionStore struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type storedStepExecution struct {
+	id       string
+	step     event_kit_api.ExperimentStepExecution
+	storedAt time.Time
+}
+
+func newStepExecutionStore(ttl time.Duration, maxEntries int) *stepExecutionStore {
+	if ttl <= 0 {
+		ttl = defaultStepExecutionTtl
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultStepExecutionMaxEntries
+	}
+	return &stepExecutionStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (s *stepExecutionStore) put(step event_kit_api.ExperimentStepExecution) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := step.Id.String()
+	if element, ok := s.entries[id]; ok {
+		s.order.Remove(element)
+	}
+	s.entries[id] = s.order.PushBack(&storedStepExecution{id: id, step: step, storedAt: s.now()})
+
+	s.evictExpired()
+	for s.order.Len() > s.maxEntries {
+		s.removeElement(s.order.Front())
+		stats.evictedStepExecutions.Add(1)
+	}
+	stats.storedStepExecutions.Store(int64(s.order.Len()))
+}
+
+func (s *stepExecutionStore) get(id string) (event_kit_api.ExperimentStepExecution, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	element, ok := s.entries[id]
+	if !ok {
+		return event_kit_api.ExperimentStepExecution{}, false
+	}
+	return element.Value.(*storedStepExecution).step, true
+}
+
+// deleteExecution removes all steps of the given experiment execution.
+func (s *stepExecutionStore) deleteExecution(executionId float32) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for element := s.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*storedStepExecution).step.ExecutionId == executionId {
+			s.removeElement(element)
+			deleted++
+		}
+		element = next
+	}
+	stats.storedStepExecutions.Store(int64(s.order.Len()))
+	return deleted
+}
+
+func (s *stepExecutionStore) evictExpired() {
+	expiredBefore := s.now().Add(-s.ttl)
+	for element := s.order.Front(); element != nil; element = s.order.Front() {
+		if element.Value.(*storedStepExecution).storedAt.After(expiredBefore) {
+			break
+		}
+		s.removeElement(element)
+		stats.expiredStepExecutions.Add(1)
+	}
+	stats.storedStepExecutions.Store(int64(s.order.Len()))
+}
+
+func (s *stepExecutionStore) removeElement(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*storedStepExecution).id)
+}
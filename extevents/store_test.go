@@ -0,0 +1,93 @@
+// store_test.go
+package extevents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+)
+
+func newStep(executionId float32) event_kit_api.ExperimentStepExecution {
+	return event_kit_api.ExperimentStepExecution{Id: uuid.New(), ExecutionId: executionId}
+}
+
+func TestStepExecutionStore_EvictsExpiredEntries(t *testing.T) {
+	store := newStepExecutionStore(time.Minute, 10)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	step := newStep(1)
+	store.put(step)
+	if _, ok := store.get(step.Id.String()); !ok {
+		t.Fatal("Expected the step to be found")
+	}
+
+	expiredBefore := GetDeliveryStats().ExpiredStepExecutions
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.get(step.Id.String()); ok {
+		t.Error("Expected the step to be expired")
+	}
+	if expired := GetDeliveryStats().ExpiredStepExecutions - expiredBefore; expired != 1 {
+		t.Errorf("Expected 1 expired step, got %d", expired)
+	}
+}
+
+func TestStepExecutionStore_EvictsOldestEntriesBeyondMaxSize(t *testing.T) {
+	store := newStepExecutionStore(time.Hour, 2)
+	evictedBefore := GetDeliveryStats().EvictedStepExecutions
+
+	first, second, third := newStep(1), newStep(1), newStep(1)
+	store.put(first)
+	store.put(second)
+	store.put(third)
+
+	if _, ok := store.get(first.Id.String()); ok {
+		t.Error("Expected the oldest step to be evicted")
+	}
+	if _, ok := store.get(third.Id.String()); !ok {
+		t.Error("Expected the newest step to be kept")
+	}
+	if evicted := GetDeliveryStats().EvictedStepExecutions - evictedBefore; evicted != 1 {
+		t.Errorf("Expected 1 evicted step, got %d", evicted)
+	}
+}
+
+func TestStepExecutionStore_DeletesStepsOfExecution(t *testing.T) {
+	store := newStepExecutionStore(time.Hour, 10)
+	first, second, other := newStep(1), newStep(1), newStep(2)
+	store.put(first)
+	store.put(second)
+	store.put(other)
+
+	if deleted := store.deleteExecution(1); deleted != 2 {
+		t.Errorf("Expected 2 deleted steps, got %d", deleted)
+	}
+	if _, ok := store.get(other.Id.String()); !ok {
+		t.Error("Expected the steps of other executions to be kept")
+	}
+}
+
+func TestOnExperimentTarget_UnknownStep(t *testing.T) {
+	stepExecutions = newStepExecutionStore(time.Hour, 10)
+	event := event_kit_api.EventRequestBody{
+		Environment:         &event_kit_api.Environment{Name: "test"},
+		ExperimentExecution: &event_kit_api.ExperimentExecution{ExecutionId: 1, ExperimentKey: "ADM-1"},
+		ExperimentStepTargetExecution: &event_kit_api.ExperimentStepTargetExecution{
+			StepExecutionId:  uuid.New(),
+			TargetAttributes: map[string][]string{"host.hostname": {"host-1"}},
+		},
+	}
+
+	result, err := onExperimentTarget(event)
+	if err != nil {
+		t.Fatalf("onExperimentTarget returned error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expected a degraded event for an unknown step")
+	}
+	if result.Properties["step_unknown"] != "true" || result.Dimensions["host.name"] != "host-1" {
+		t.Errorf("Expected a degraded event with the target dimensions, got %v", result)
+	}
+}
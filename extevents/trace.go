@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extevents
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	traceServiceName = "steadybit"
+	traceScopeName   = "github.com/steadybit/extension-splunk"
+)
+
+// toSpan turns the event into a span of the experiment execution's trace: the experiment is the root span, the steps
+// are its children and the targets are the children of their step. Spans are created once their part of the
+// experiment ended. The trace and span ids are derived from the execution ids, so the spans of one execution end up in
+// the same trace without keeping any state.
+func toSpan(event event_kit_api.EventRequestBody) *tracepb.Span {
+	switch {
+	case event.ExperimentStepTargetExecution != nil:
+		return targetSpan(event)
+	case event.ExperimentStepExecution != nil:
+		return stepSpan(event)
+	case event.ExperimentExecution != nil:
+		return experimentSpan(event)
+	}
+	return nil
+}
+
+func experimentSpan(event event_kit_api.EventRequestBody) *tracepb.Span {
+	execution := event.ExperimentExecution
+	if execution.EndedTime == nil {
+		return nil
+	}
+
+	tags := getEventBaseTags(event)
+	maps.Copy(tags, getExecutionTags(event))
+	tags = eventRedaction.apply(tags)
+
+	// The name is taken from the redacted tags, so it doesn't reveal redacted values.
+	name := tags["exp_key"]
+	if tags["exp_name"] != "" {
+		name = fmt.Sprintf("%s %s", tags["exp_key"], tags["exp_name"])
+	}
+
+	span := newSpan(event, execution.ExecutionId, experimentSpanId(event, execution.ExecutionId), nil, name, &execution.StartedTime, execution.EndedTime, tags)
+	if isFailedState(string(execution.State)) {
+		span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR}
+		if execution.Reason != nil {
+			// The reason is reported as step_error of the failing step, so it is redacted the same way.
+			span.Status.Message = eventRedaction.applyToValue("step_error", *execution.Reason)
+		}
+	}
+	return span
+}
+
+func stepSpan(event event_kit_api.EventRequestBody) *tracepb.Span {
+	step := event.ExperimentStepExecution
+	if step.EndedTime == nil {
+		return nil
+	}
+
+	tags := getEventBaseTags(event)
+	maps.Copy(tags, getExecutionTags(event))
+	maps.Copy(tags, getStepTags(*step))
+	maps.Copy(tags, getStepOutcomeTags(event))
+	tags = eventRedaction.apply(tags)
+
+	span := newSpan(event, step.ExecutionId, spanId(event, "step", step.Id.String()), experimentSpanId(event, step.ExecutionId), stepSpanName(*step, tags), step.StartedTime, step.EndedTime, tags)
+	if isFailedState(string(step.State)) {
+		span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: tags["step_error"]}
+	}
+	return span
+}
+
+func targetSpan(event event_kit_api.EventRequestBody) *tracepb.Span {
+	target := event.ExperimentStepTargetExecution
+	if target.EndedTime == nil {
+		return nil
+	}
+
+	tags := getEventBaseTags(event)
+	maps.Copy(tags, getExecutionTags(event))
+	maps.Copy(tags, getTargetTags(*target))
+	maps.Copy(tags, getTargetDimensions(*target))
+	tags["target_name"] = target.TargetName
+	tags["target_type"] = target.TargetType
+	tags = eventRedaction.apply(tags)
+
+	span := newSpan(event, target.ExecutionId, spanId(event, "target", target.Id.String()), spanId(event, "step", target.StepExecutionId.String()), tags["target_name"], target.StartedTime, target.EndedTime, tags)
+	if isFailedState(string(target.State)) {
+		span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR}
+	}
+	return span
+}
+
+func newSpan(event event_kit_api.EventRequestBody, executionId float32, id []byte, parentId []byte, name string, started *time.Time, ended *time.Time, tags map[string]string) *tracepb.Span {
+	start := *ended
+	if started != nil && !started.IsZero() {
+		start = *started
+	}
+	return &tracepb.Span{
+		TraceId:           traceId(event, executionId),
+		SpanId:            id,
+		ParentSpanId:      parentId,
+		Name:              name,
+		Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(start.UnixNano()),
+		EndTimeUnixNano:   uint64(ended.UnixNano()),
+		Attributes:        toAttributes(tags),
+	}
+}
+
+// stepSpanName names the step by its label, action name or action id. They are taken from the redacted tags, so the
+// name doesn't reveal redacted values.
+func stepSpanName(step event_kit_api.ExperimentStepExecution, tags map[string]string) string {
+	for _, key := range []string{"step_label", "step_name", "step_action_id"} {
+		if tags[key] != "" {
+			return tags[key]
+		}
+	}
+	return string(step.Type)
+}
+
+func isFailedState(state string) bool {
+	return state == "failed" || state == "errored"
+}
+
+func traceId(event event_kit_api.EventRequestBody, executionId float32) []byte {
+	hash := sha256.Sum256(fmt.Appendf(nil, "%s/execution/%.0f", event.Tenant.Key, executionId))
+	return hash[:16]
+}
+
+func experimentSpanId(event event_kit_api.EventRequestBody, executionId float32) []byte {
+	return spanId(event, "experiment", fmt.Sprintf("%.0f", executionId))
+}
+
+func spanId(event event_kit_api.EventRequestBody, kind string, id string) []byte {
+	hash := sha256.Sum256([]byte(strings.Join([]string{event.Tenant.Key, kind, id}, "/")))
+	return hash[:8]
+}
+
+func toAttributes(tags map[string]string) []*commonpb.KeyValue {
+	attributes := make([]*commonpb.KeyValue, 0, len(tags))
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		attributes = append(attributes, stringAttribute(key, tags[key]))
+	}
+	return attributes
+}
+
+func stringAttribute(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+// handlePostSpans exports the spans via OTLP/HTTP. TracesData shares the wire format of the ExportTraceServiceRequest
+// expected by the endpoint.
+func handlePostSpans(ctx context.Context, client *resty.Client, spans []*tracepb.Span) error {
+	data, err := proto.Marshal(&tracepb.TracesData{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{stringAttribute("service.name", traceServiceName)},
+			},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Scope: &commonpb.InstrumentationScope{Name: traceScopeName},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	res, err := client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/x-protobuf").
+		SetBody(data).
+		Post("/v2/trace/otlp")
+
+	if err != nil {
+		return fmt.Errorf("failed to post spans: %w", err)
+	}
+
+	if !res.IsSuccess() {
+		return fmt.Errorf("splunk ingest API responded with unexpected status code %d while posting spans. Full response: %v", res.StatusCode(), res.String())
+	}
+
+	log.Debug().Msgf("Posted %d spans to Splunk.", len(spans))
+	return nil
+}
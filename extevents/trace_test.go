@@ -0,0 +1,142 @@
+// trace_test.go
+package extevents
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/steadybit/event-kit/go/event_kit_api"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func traceTestEvents() (experiment, step, target event_kit_api.EventRequestBody) {
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	ended := started.Add(time.Minute)
+	reason := "Attack failed"
+	stepId := uuid.New()
+	base := event_kit_api.EventRequestBody{
+		Environment: &event_kit_api.Environment{Name: "test"},
+		Tenant:      event_kit_api.Tenant{Key: "demo", Name: "Demo"},
+		ExperimentExecution: &event_kit_api.ExperimentExecution{
+			ExecutionId:   7,
+			ExperimentKey: "ADM-1",
+			Name:          "Latency",
+			StartedTime:   started,
+			EndedTime:     &ended,
+			State:         "failed",
+			Reason:        &reason,
+		},
+	}
+
+	experiment = base
+	step = base
+	step.ExperimentStepExecution = &event_kit_api.ExperimentStepExecution{
+		Id:          stepId,
+		ExecutionId: 7,
+		ActionName:  new("Container Latency"),
+		State:       "completed",
+		Type:        event_kit_api.Action,
+		ActionId:    new("com.steadybit.extension_container.network_delay"),
+		StartedTime: &started,
+		EndedTime:   &ended,
+	}
+	target = base
+	target.ExperimentStepTargetExecution = &event_kit_api.ExperimentStepTargetExecution{
+		Id:              uuid.New(),
+		ExecutionId:     7,
+		StepExecutionId: stepId,
+		TargetName:      "checkout",
+		State:           "completed",
+		StartedTime:     &started,
+		EndedTime:       &ended,
+	}
+	return
+}
+
+func TestToSpan_BuildsHierarchy(t *testing.T) {
+	experimentEvent, stepEvent, targetEvent := traceTestEvents()
+	experiment := toSpan(experimentEvent)
+	step := toSpan(stepEvent)
+	target := toSpan(targetEvent)
+
+	if experiment == nil || step == nil || target == nil {
+		t.Fatal("Expected spans for all ended executions")
+	}
+	if !bytes.Equal(experiment.TraceId, step.TraceId) || !bytes.Equal(step.TraceId, target.TraceId) {
+		t.Error("Expected all spans to share the trace id of the execution")
+	}
+	if experiment.ParentSpanId != nil {
+		t.Error("Expected the experiment to be the root span")
+	}
+	if !bytes.Equal(step.ParentSpanId, experiment.SpanId) {
+		t.Error("Expected the step span to be a child of the experiment span")
+	}
+	if !bytes.Equal(target.ParentSpanId, step.SpanId) {
+		t.Error("Expected the target span to be a child of the step span")
+	}
+	if experiment.Name != "ADM-1 Latency" || step.Name != "Container Latency" || target.Name != "checkout" {
+		t.Errorf("Unexpected span names %q, %q, %q", experiment.Name, step.Name, target.Name)
+	}
+	if experiment.Status == nil || experiment.Status.Code != tracepb.Status_STATUS_CODE_ERROR || experiment.Status.Message != "Attack failed" {
+		t.Errorf("Expected an error status for the failed experiment, got %v", experiment.Status)
+	}
+	if step.Status != nil {
+		t.Errorf("Expected no status for the completed step, got %v", step.Status)
+	}
+	if time.Duration(experiment.EndTimeUnixNano-experiment.StartTimeUnixNano) != time.Minute {
+		t.Error("Expected the span to cover the execution")
+	}
+}
+
+func TestToSpan_IgnoresStartedExecutions(t *testing.T) {
+	experimentEvent, stepEvent, _ := traceTestEvents()
+	experimentEvent.ExperimentExecution.EndedTime = nil
+	stepEvent.ExperimentStepExecution.EndedTime = nil
+
+	if span := toSpan(experimentEvent); span != nil {
+		t.Errorf("Expected no span for a started experiment, got %v", span)
+	}
+	if span := toSpan(stepEvent); span != nil {
+		t.Errorf("Expected no span for a started step, got %v", span)
+	}
+}
+
+func TestHandlePostSpans(t *testing.T) {
+	var received tracepb.TracesData
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/trace/otlp" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("unexpected content type: %s", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := proto.Unmarshal(body, &received); err != nil {
+			t.Errorf("failed to decode spans: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	experimentEvent, _, _ := traceTestEvents()
+	client := resty.New().SetBaseURL(ts.URL).SetHeader("Content-Type", "application/json")
+	if err := handlePostSpans(context.Background(), client, []*tracepb.Span{toSpan(experimentEvent)}); err != nil {
+		t.Fatalf("handlePostSpans returned error: %v", err)
+	}
+
+	spans := received.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()
+	if len(spans) != 1 || spans[0].Name != "ADM-1 Latency" {
+		t.Errorf("Expected the experiment span, got %v", spans)
+	}
+	if attribute := received.GetResourceSpans()[0].GetResource().GetAttributes()[0]; attribute.GetValue().GetStringValue() != traceServiceName {
+		t.Errorf("Expected the service name resource attribute, got %v", attribute)
+	}
+}
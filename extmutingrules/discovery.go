@@ -0,0 +1,198 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extmutingrules
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_api"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_commons"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_sdk"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extdetectors"
+	"time"
+)
+
+const (
+	TargetType           = "com.steadybit.extension_splunk.muting_rule"
+	targetIcon           = "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCAyNCAyNCIgZmlsbD0ibm9uZSI+PHBhdGggZD0iTTE4IDEwdjQuN2wxLjYgMi4zTTYgOC41VjE0LjdMNCAxOGgxMk04LjUgNC4zQTYgNiAwIDAgMSAxOCAxME0xMCAyMWg0TTMgM2wxOCAxOCIgc3Ryb2tlPSJjdXJyZW50Q29sb3IiIHN0cm9rZS13aWR0aD0iMiIgc3Ryb2tlLWxpbmVjYXA9InJvdW5kIiBzdHJva2UtbGluZWpvaW49InJvdW5kIi8+PC9zdmc+"
+	attributeID          = "splunk.mutingRule.id"
+	attributeDescription = "splunk.mutingRule.description"
+	attributeFilter      = "splunk.mutingRule.filter"
+	attributeStartTime   = "splunk.mutingRule.startTime"
+	attributeStopTime    = "splunk.mutingRule.stopTime"
+	attributeCreator     = "splunk.mutingRule.creator"
+	attributeDetectorID  = "splunk.mutingRule.detectorId"
+)
+
+type mutingRuleDiscovery struct {
+}
+
+var (
+	_           discovery_kit_sdk.TargetDescriber    = (*mutingRuleDiscovery)(nil)
+	_           discovery_kit_sdk.AttributeDescriber = (*mutingRuleDiscovery)(nil)
+	RestyClient *resty.Client
+)
+
+func NewMutingRuleDiscovery() discovery_kit_sdk.TargetDiscovery {
+	discovery := &mutingRuleDiscovery{}
+	return discovery_kit_sdk.NewCachedTargetDiscovery(discovery,
+		discovery_kit_sdk.WithRefreshTargetsNow(),
+		discovery_kit_sdk.WithRefreshTargetsInterval(context.Background(), 1*time.Minute),
+	)
+}
+
+func (d *mutingRuleDiscovery) Describe() discovery_kit_api.DiscoveryDescription {
+	return discovery_kit_api.DiscoveryDescription{
+		Id: TargetType,
+		Discover: discovery_kit_api.DescribingEndpointReferenceWithCallInterval{
+			CallInterval: new("1m"),
+		},
+	}
+}
+
+func (d *mutingRuleDiscovery) DescribeTarget() discovery_kit_api.TargetDescription {
+	return discovery_kit_api.TargetDescription{
+		Id:       TargetType,
+		Label:    discovery_kit_api.PluralLabel{One: "Splunk muting rule", Other: "Splunk muting rules"},
+		Category: new("monitoring"),
+		Version:  extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:     new(targetIcon),
+		Table: discovery_kit_api.Table{
+			Columns: []discovery_kit_api.Column{
+				{Attribute: attributeDescription},
+				{Attribute: attributeStartTime},
+				{Attribute: attributeStopTime},
+			},
+			OrderBy: []discovery_kit_api.OrderBy{
+				{
+					Attribute: attributeStartTime,
+					Direction: "ASC",
+				},
+			},
+		},
+	}
+}
+
+func (d *mutingRuleDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescription {
+	return []discovery_kit_api.AttributeDescription{
+		{
+			Attribute: attributeID,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "ID",
+				Other: "IDs",
+			},
+		}, {
+			Attribute: attributeDescription,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Description",
+				Other: "Descriptions",
+			},
+		}, {
+			Attribute: attributeFilter,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Filter",
+				Other: "Filters",
+			},
+		}, {
+			Attribute: attributeStartTime,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Start time",
+				Other: "Start times",
+			},
+		}, {
+			Attribute: attributeStopTime,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Stop time",
+				Other: "Stop times",
+			},
+		}, {
+			Attribute: attributeCreator,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Creator",
+				Other: "Creators",
+			},
+		}, {
+			Attribute: attributeDetectorID,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Detector ID",
+				Other: "Detector IDs",
+			},
+		},
+	}
+}
+
+func (d *mutingRuleDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_api.Target, error) {
+	return getAllMutingRules(ctx, RestyClient)
+}
+
+// getAllMutingRules fails if any page can't be retrieved, so the cached discovery keeps the previous targets instead of
+// replacing them with an incomplete list.
+func getAllMutingRules(ctx context.Context, client *resty.Client) ([]discovery_kit_api.Target, error) {
+	rules, err := extdetectors.GetMutingRules(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]discovery_kit_api.Target, 0, len(rules))
+	for _, rule := range rules {
+		// Expired rules don't mute anything anymore, but Splunk keeps them around.
+		if rule.IsExpired(now) {
+			continue
+		}
+
+		label := rule.Description
+		if label == "" {
+			label = rule.ID
+		}
+		attributes := map[string][]string{
+			attributeID:          {rule.ID},
+			attributeDescription: {rule.Description},
+			attributeStartTime:   {formatTime(rule.StartTime)},
+			attributeCreator:     {rule.Creator},
+		}
+		if filters := formatFilters(rule.Filters); len(filters) > 0 {
+			attributes[attributeFilter] = filters
+		}
+		if rule.StopTime > 0 {
+			attributes[attributeStopTime] = []string{formatTime(rule.StopTime)}
+		}
+		if detectorIds := rule.DetectorIds(); len(detectorIds) > 0 {
+			attributes[attributeDetectorID] = detectorIds
+		}
+		result = append(result, discovery_kit_api.Target{
+			Id:         rule.ID,
+			TargetType: TargetType,
+			Label:      label,
+			Attributes: attributes,
+		})
+	}
+
+	return discovery_kit_commons.ApplyAttributeExcludes(result, config.Config.DiscoveryAttributesExcludesMutingRule), nil
+}
+
+// formatFilters renders each filter value as "property=value", or "property!=value" for negated filters.
+func formatFilters(filters []extdetectors.MutingFilter) []string {
+	var result []string
+	for _, filter := range filters {
+		operator := "="
+		if filter.Not {
+			operator = "!="
+		}
+		for _, value := range filter.PropertyValue {
+			result = append(result, filter.Property+operator+value)
+		}
+	}
+	return result
+}
+
+func formatTime(timestamp int64) string {
+	return time.UnixMilli(timestamp).UTC().Format(time.RFC3339)
+}
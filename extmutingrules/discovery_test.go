@@ -0,0 +1,144 @@
+// discovery_test.go
+package extmutingrules
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/extension-splunk/config"
+)
+
+func TestDescribeTarget(t *testing.T) {
+	td := (&mutingRuleDiscovery{}).DescribeTarget()
+	if td.Id != TargetType {
+		t.Errorf("DescribeTarget() Id = %s; want %s", td.Id, TargetType)
+	}
+	if td.Label.One != "Splunk muting rule" || td.Label.Other != "Splunk muting rules" {
+		t.Errorf("DescribeTarget() Label = %+v", td.Label)
+	}
+}
+
+func TestDescribeAttributes(t *testing.T) {
+	attrs := (&mutingRuleDiscovery{}).DescribeAttributes()
+	expected := []string{
+		attributeID,
+		attributeDescription,
+		attributeFilter,
+		attributeStartTime,
+		attributeStopTime,
+		attributeCreator,
+		attributeDetectorID,
+	}
+	if len(attrs) != len(expected) {
+		t.Fatalf("DescribeAttributes() length = %d; want %d", len(attrs), len(expected))
+	}
+	for i, attr := range attrs {
+		if attr.Attribute != expected[i] {
+			t.Errorf("DescribeAttributes() attr[%d] = %s; want %s", i, attr.Attribute, expected[i])
+		}
+	}
+}
+
+func newTestServer(body string, statusCode int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/alertmuting" {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestDiscoverTargets(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	stop := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	ts := newTestServer(`{"count": 3, "results": [
+		{"id": "rule1", "description": "Maintenance", "creator": "user1", "startTime": `+itoa(start.UnixMilli())+`, "stopTime": `+itoa(stop.UnixMilli())+`,
+		 "filters": [{"property": "sf_detectorId", "propertyValue": ["det1", "det2"], "NOT": false}, {"property": "env", "propertyValue": "prod", "NOT": true}]},
+		{"id": "rule2", "creator": "user2", "startTime": `+itoa(start.UnixMilli())+`, "stopTime": 0, "filters": []},
+		{"id": "expired", "startTime": `+itoa(start.UnixMilli())+`, "stopTime": `+itoa(start.Add(time.Hour).UnixMilli())+`}
+	]}`, http.StatusOK)
+	defer ts.Close()
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	targets, err := (&mutingRuleDiscovery{}).DiscoverTargets(context.Background())
+	if err != nil {
+		t.Fatalf("DiscoverTargets returned error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("Expected the expired rule to be skipped, got %d targets", len(targets))
+	}
+
+	attrs := targets[0].Attributes
+	if targets[0].Id != "rule1" || targets[0].Label != "Maintenance" || targets[0].TargetType != TargetType {
+		t.Errorf("Unexpected target %+v", targets[0])
+	}
+	if filters := attrs[attributeFilter]; len(filters) != 3 || filters[0] != "sf_detectorId=det1" || filters[2] != "env!=prod" {
+		t.Errorf("Unexpected filters %v", filters)
+	}
+	if ids := attrs[attributeDetectorID]; len(ids) != 2 || ids[0] != "det1" || ids[1] != "det2" {
+		t.Errorf("Unexpected detector ids %v", ids)
+	}
+	if attrs[attributeStartTime][0] != "2025-06-01T10:00:00Z" || attrs[attributeStopTime][0] != stop.Format(time.RFC3339) {
+		t.Errorf("Unexpected start and stop times %v / %v", attrs[attributeStartTime], attrs[attributeStopTime])
+	}
+	if attrs[attributeCreator][0] != "user1" {
+		t.Errorf("Unexpected creator %v", attrs[attributeCreator])
+	}
+
+	if targets[1].Label != "rule2" {
+		t.Errorf("Expected the id as label of rules without description, got %s", targets[1].Label)
+	}
+	if _, ok := targets[1].Attributes[attributeStopTime]; ok {
+		t.Error("Expected no stop time for rules muting until they are removed")
+	}
+}
+
+func TestDiscoverTargets_UnexpectedStatus(t *testing.T) {
+	ts := newTestServer(`{}`, http.StatusInternalServerError)
+	defer ts.Close()
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	if _, err := (&mutingRuleDiscovery{}).DiscoverTargets(context.Background()); err == nil {
+		t.Error("Expected an error for an unexpected status code")
+	}
+}
+
+func TestDiscoverTargets_NotFound(t *testing.T) {
+	ts := newTestServer(`{}`, http.StatusNotFound)
+	defer ts.Close()
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	if _, err := (&mutingRuleDiscovery{}).DiscoverTargets(context.Background()); err == nil {
+		t.Error("Expected an error instead of an empty list of muting rules")
+	}
+}
+
+func TestDiscoverTargets_AttributeExclusion(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.DiscoveryAttributesExcludesMutingRule = []string{attributeCreator}
+
+	ts := newTestServer(`{"count": 1, "results": [{"id": "rule1", "creator": "user1", "startTime": 1}]}`, http.StatusOK)
+	defer ts.Close()
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	targets, _ := (&mutingRuleDiscovery{}).DiscoverTargets(context.Background())
+	if len(targets) != 1 {
+		t.Fatalf("Expected 1 target, got %d", len(targets))
+	}
+	if _, ok := targets[0].Attributes[attributeCreator]; ok {
+		t.Error("Expected the creator attribute to be excluded")
+	}
+}
+
+func itoa(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
@@ -0,0 +1,443 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extsignalflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extclient/signalflow"
+)
+
+type SignalFlowCheckAction struct{}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[SignalFlowCheckState]           = (*SignalFlowCheckAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[SignalFlowCheckState] = (*SignalFlowCheckAction)(nil)
+)
+
+const (
+	ActionId                  = "com.steadybit.extension_splunk.signalflow.check"
+	actionIcon                = "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCAyNCAyNCIgZmlsbD0ibm9uZSIgc3Ryb2tlPSJjdXJyZW50Q29sb3IiIHN0cm9rZS13aWR0aD0iMiIgc3Ryb2tlLWxpbmVjYXA9InJvdW5kIiBzdHJva2UtbGluZWpvaW49InJvdW5kIj48cGF0aCBkPSJNMyAzdjE4aDE4Ii8+PHBhdGggZD0ibTcgMTQgNC00IDQgNCA1LTUiLz48L3N2Zz4="
+	stateCheckModeAtLeastOnce = "atLeastOnce"
+	stateCheckModeAllTheTime  = "allTheTime"
+	metricName                = "splunk_signalflow_value"
+	// defaultSettleDelay is used as settle delay if Splunk chooses the resolution.
+	defaultSettleDelay = 10 * time.Second
+
+	ConditionBelow        = "below"
+	ConditionBelowOrEqual = "belowOrEqual"
+	ConditionAbove        = "above"
+	ConditionAboveOrEqual = "aboveOrEqual"
+)
+
+var RestyClient *resty.Client
+
+type SignalFlowCheckState struct {
+	Program        string
+	Resolution     time.Duration
+	Condition      string
+	Threshold      float64
+	Start          time.Time
+	End            time.Time
+	StateCheckMode string
+	FailEarly      bool
+	// LastTimestamp is the logical timestamp of the latest value seen, so every status call only computes new values.
+	LastTimestamp     int64
+	DataSeen          bool
+	StateCheckSuccess bool
+	// DeviationSeen and DeviationTitle are used in 'fail at end' mode (FailEarly = false) to remember
+	// that a deviating value was observed during the step so the failure can be reported once the step ends.
+	DeviationSeen  bool
+	DeviationTitle string
+	// ConsecutiveApiErrors counts the executions of the program in a row that failed for other reasons than the program.
+	ConsecutiveApiErrors int
+}
+
+func NewSignalFlowCheckAction() action_kit_sdk.Action[SignalFlowCheckState] {
+	return &SignalFlowCheckAction{}
+}
+
+func (m *SignalFlowCheckAction) NewEmptyState() SignalFlowCheckState {
+	return SignalFlowCheckState{}
+}
+
+func (m *SignalFlowCheckAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          ActionId,
+		Label:       "Check SignalFlow Metric",
+		Description: "Execute a SignalFlow program and check its values against a threshold.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(actionIcon),
+		Technology:  new("Splunk"),
+		Category:    new("Splunk"),
+		Kind:        action_kit_api.Check,
+		TimeControl: action_kit_api.TimeControlInternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new(""),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:        "program",
+				Label:       "SignalFlow Program",
+				Description: new("The SignalFlow program to execute. All published time series are checked, e.g. data('service.request.duration.ns.p99', filter=filter('sf_service', 'checkout')).publish()"),
+				Type:        action_kit_api.ActionParameterTypeTextarea,
+				Required:    new(true),
+				Order:       new(1),
+			},
+			{
+				Name:         "condition",
+				Label:        "Condition",
+				Description:  new("How the values are compared to the threshold."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(ConditionBelow),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{
+						Label: "Below",
+						Value: ConditionBelow,
+					},
+					action_kit_api.ExplicitParameterOption{
+						Label: "Below or equal",
+						Value: ConditionBelowOrEqual,
+					},
+					action_kit_api.ExplicitParameterOption{
+						Label: "Above",
+						Value: ConditionAbove,
+					},
+					action_kit_api.ExplicitParameterOption{
+						Label: "Above or equal",
+						Value: ConditionAboveOrEqual,
+					},
+				}),
+				Required: new(true),
+				Order:    new(2),
+			},
+			{
+				Name:        "threshold",
+				Label:       "Threshold",
+				Description: new("The threshold the values are compared to, in the unit of the program's output."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+				Order:       new(3),
+			},
+			{
+				Name:         "stateCheckMode",
+				Label:        "State Check Mode",
+				Description:  new("How often should the condition be met ?"),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(stateCheckModeAllTheTime),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{
+						Label: "All the time",
+						Value: stateCheckModeAllTheTime,
+					},
+					action_kit_api.ExplicitParameterOption{
+						Label: "At least once",
+						Value: stateCheckModeAtLeastOnce,
+					},
+				}),
+				Required: new(true),
+				Order:    new(4),
+			},
+			{
+				Name:         "failEarly",
+				Label:        "Fail early",
+				Description:  new("If enabled, the check fails as soon as a deviating value is observed. If disabled, the check keeps collecting values for the whole duration and only fails at the end of the step. Only affects the 'All the time' mode; 'At least once' can only be evaluated at the end of the step."),
+				Type:         action_kit_api.ActionParameterTypeBoolean,
+				DefaultValue: new("true"),
+				Advanced:     new(true),
+				Required:     new(false),
+				Order:        new(5),
+			},
+			{
+				Name:        "resolution",
+				Label:       "Resolution",
+				Description: new("The resolution of the computation. Splunk chooses the resolution based on the data if not set."),
+				Type:        action_kit_api.ActionParameterTypeDuration,
+				Advanced:    new(true),
+				Required:    new(false),
+				Order:       new(6),
+			},
+		},
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "Splunk SignalFlow Values",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: metricName,
+					From:       "splunk.signalflow.series",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeSelect,
+				},
+				Grouping: new(action_kit_api.LineChartWidgetGroupingConfig{
+					ShowSummary: new(true),
+					Groups: []action_kit_api.LineChartWidgetGroup{
+						{
+							Title: "Condition met",
+							Color: "success",
+							Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+								Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+								Key:   "state",
+								Value: "success",
+							},
+						},
+						{
+							Title: "Condition violated",
+							Color: "danger",
+							Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+								Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+								Key:   "state",
+								Value: "danger",
+							},
+						},
+					},
+				}),
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("Value"),
+					AdditionalContent: []action_kit_api.LineChartWidgetTooltipContent{
+						{
+							From:  "splunk.signalflow.series",
+							Title: "Series",
+						},
+						{
+							From:  "tooltip",
+							Title: "Condition",
+						},
+					},
+				}),
+			},
+		}),
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("5s"),
+		}),
+	}
+}
+
+func (m *SignalFlowCheckAction) Prepare(_ context.Context, state *SignalFlowCheckState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	program := strings.TrimSpace(extutil.ToString(request.Config["program"]))
+	if program == "" {
+		return nil, new(extension_kit.ToError("The SignalFlow program must not be empty.", nil))
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(extutil.ToString(request.Config["threshold"])), 64)
+	if err != nil {
+		return nil, new(extension_kit.ToError(fmt.Sprintf("The threshold '%v' is not a number.", request.Config["threshold"]), err))
+	}
+
+	condition := ConditionBelow
+	if request.Config["condition"] != nil {
+		condition = fmt.Sprintf("%v", request.Config["condition"])
+	}
+	switch condition {
+	case ConditionBelow, ConditionBelowOrEqual, ConditionAbove, ConditionAboveOrEqual:
+	default:
+		return nil, new(extension_kit.ToError(fmt.Sprintf("Unknown condition '%s'.", condition), nil))
+	}
+
+	stateCheckMode := stateCheckModeAllTheTime
+	if request.Config["stateCheckMode"] != nil {
+		stateCheckMode = fmt.Sprintf("%v", request.Config["stateCheckMode"])
+	}
+
+	state.FailEarly = true
+	if request.Config["failEarly"] != nil {
+		state.FailEarly = extutil.ToBool(request.Config["failEarly"])
+	}
+
+	if request.Config["resolution"] != nil {
+		state.Resolution = time.Duration(extutil.ToInt64(request.Config["resolution"])) * time.Millisecond
+	}
+
+	duration := request.Config["duration"].(float64)
+	state.Start = time.Now()
+	state.End = state.Start.Add(time.Millisecond * time.Duration(duration))
+	state.Program = program
+	state.Condition = condition
+	state.Threshold = threshold
+	state.StateCheckMode = stateCheckMode
+
+	return nil, nil
+}
+
+func (m *SignalFlowCheckAction) Start(ctx context.Context, state *SignalFlowCheckState) (*action_kit_api.StartResult, error) {
+	statusResult, err := SignalFlowCheckStatus(ctx, state, RestyClient)
+	if statusResult == nil {
+		return nil, err
+	}
+	return &action_kit_api.StartResult{
+		Artifacts: statusResult.Artifacts,
+		Error:     statusResult.Error,
+		Messages:  statusResult.Messages,
+		Metrics:   statusResult.Metrics,
+	}, err
+}
+
+func (m *SignalFlowCheckAction) Status(ctx context.Context, state *SignalFlowCheckState) (*action_kit_api.StatusResult, error) {
+	return SignalFlowCheckStatus(ctx, state, RestyClient)
+}
+
+func SignalFlowCheckStatus(ctx context.Context, state *SignalFlowCheckState, client *resty.Client) (*action_kit_api.StatusResult, error) {
+	now := time.Now()
+	completed := now.After(state.End)
+
+	from := state.Start
+	if state.LastTimestamp > 0 {
+		from = time.UnixMilli(state.LastTimestamp + 1)
+	}
+	// Immediate computations don't wait for late data, so the most recent values are left for the next call until the
+	// data had time to arrive. The last call evaluates everything up to the end of the step.
+	to := now.Add(-settleDelay(state.Resolution))
+	if completed {
+		to = state.End
+	}
+
+	var values []seriesValue
+	var err error
+	if to.After(from) {
+		values, err = executeProgram(ctx, client, state.Program, from, to, state.Resolution)
+	}
+	if programErr, ok := errors.AsType[*signalflow.ErrorMessage](err); ok {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("The SignalFlow program failed: %s", programErr.Message),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	} else if err != nil {
+		state.ConsecutiveApiErrors++
+		log.Warn().Err(err).Msgf("Failed to execute the SignalFlow program (%d consecutive errors).", state.ConsecutiveApiErrors)
+		if state.ConsecutiveApiErrors > config.Config.CheckApiErrorTolerance || completed {
+			return &action_kit_api.StatusResult{
+				Completed: true,
+				Error: new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("Failed to execute the SignalFlow program: %v", err),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+	state.ConsecutiveApiErrors = 0
+
+	var checkError *action_kit_api.ActionKitError
+	metrics := make([]action_kit_api.Metric, 0, len(values))
+	for _, value := range values {
+		met := conditionMet(state.Condition, value.Value, state.Threshold)
+		state.DataSeen = true
+		state.LastTimestamp = max(state.LastTimestamp, value.Timestamp)
+		metrics = append(metrics, toMetric(state, value, met))
+
+		if state.StateCheckMode == stateCheckModeAtLeastOnce {
+			state.StateCheckSuccess = state.StateCheckSuccess || met
+		} else if !met && checkError == nil {
+			if state.FailEarly {
+				checkError = new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("The value %g of '%s' is not %s.", value.Value, value.Series, describeCondition(state.Condition, state.Threshold)),
+					Status: extutil.Ptr(action_kit_api.Failed),
+				})
+			} else if !state.DeviationSeen {
+				state.DeviationSeen = true
+				state.DeviationTitle = fmt.Sprintf("The value %g of '%s' was not %s.", value.Value, value.Series, describeCondition(state.Condition, state.Threshold))
+			}
+		}
+	}
+
+	if completed && checkError == nil {
+		switch {
+		case !state.DataSeen:
+			checkError = new(action_kit_api.ActionKitError{
+				Title:  "The SignalFlow program didn't return any values.",
+				Status: extutil.Ptr(action_kit_api.Failed),
+			})
+		case state.StateCheckMode == stateCheckModeAtLeastOnce && !state.StateCheckSuccess:
+			checkError = new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("The values were not %s at least once.", describeCondition(state.Condition, state.Threshold)),
+				Status: extutil.Ptr(action_kit_api.Failed),
+			})
+		case state.StateCheckMode == stateCheckModeAllTheTime && state.DeviationSeen:
+			checkError = new(action_kit_api.ActionKitError{
+				Title:  state.DeviationTitle,
+				Status: extutil.Ptr(action_kit_api.Failed),
+			})
+		}
+	}
+
+	return &action_kit_api.StatusResult{
+		Completed: completed,
+		Error:     checkError,
+		Metrics:   new(metrics),
+	}, nil
+}
+
+// settleDelay is the time given to the data of the most recent interval to arrive before it is evaluated.
+func settleDelay(resolution time.Duration) time.Duration {
+	if resolution > 0 {
+		return resolution
+	}
+	return defaultSettleDelay
+}
+
+func conditionMet(condition string, value float64, threshold float64) bool {
+	switch condition {
+	case ConditionBelowOrEqual:
+		return value <= threshold
+	case ConditionAbove:
+		return value > threshold
+	case ConditionAboveOrEqual:
+		return value >= threshold
+	}
+	return value < threshold
+}
+
+func describeCondition(condition string, threshold float64) string {
+	switch condition {
+	case ConditionBelowOrEqual:
+		return fmt.Sprintf("below or equal to %g", threshold)
+	case ConditionAbove:
+		return fmt.Sprintf("above %g", threshold)
+	case ConditionAboveOrEqual:
+		return fmt.Sprintf("above or equal to %g", threshold)
+	}
+	return fmt.Sprintf("below %g", threshold)
+}
+
+func toMetric(state *SignalFlowCheckState, value seriesValue, met bool) action_kit_api.Metric {
+	metricState := "success"
+	if !met {
+		metricState = "danger"
+	}
+	return action_kit_api.Metric{
+		Name: new(metricName),
+		Metric: map[string]string{
+			"splunk.signalflow.series": value.Series,
+			"state":                    metricState,
+			"tooltip":                  describeCondition(state.Condition, state.Threshold),
+		},
+		Timestamp: time.UnixMilli(value.Timestamp),
+		Value:     value.Value,
+	}
+}
@@ -0,0 +1,227 @@
+// check_test.go
+package extsignalflow
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	actionApi "github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extclient/signalflow"
+	"github.com/steadybit/extension-splunk/extclient/signalflow/signalflowtest"
+)
+
+const testProgram = "data('latency').publish()"
+
+// newTestServer responds to every execution with the given values of a single time series.
+func newTestServer(values ...float64) *signalflowtest.Server {
+	server := signalflowtest.NewServer().
+		AddSeries("AAA", map[string]any{"sf_metric": "latency", "sf_key": []any{"sf_metric", "service"}, "service": "checkout"})
+	for i, value := range values {
+		server.AddData(time.Now().Add(-time.Minute).UnixMilli()+int64(i+1)*1000, map[string]float64{"AAA": value})
+	}
+	return server
+}
+
+func newState(stateCheckMode string, failEarly bool, end time.Time) *SignalFlowCheckState {
+	return &SignalFlowCheckState{
+		Program:        testProgram,
+		Condition:      ConditionBelow,
+		Threshold:      500,
+		Start:          time.Now().Add(-time.Minute),
+		End:            end,
+		StateCheckMode: stateCheckMode,
+		FailEarly:      failEarly,
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	desc := (&SignalFlowCheckAction{}).Describe()
+	if desc.Id != ActionId {
+		t.Errorf("Describe() Id = %s; want %s", desc.Id, ActionId)
+	}
+	if desc.TargetSelection != nil {
+		t.Error("Expected a check without target selection")
+	}
+	if desc.Widgets == nil || len(*desc.Widgets) != 1 {
+		t.Fatal("Expected a line chart widget")
+	}
+	if _, ok := (*desc.Widgets)[0].(actionApi.LineChartWidget); !ok {
+		t.Errorf("Expected a line chart widget, got %T", (*desc.Widgets)[0])
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	action := &SignalFlowCheckAction{}
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, actionApi.PrepareActionRequestBody{
+		Config: map[string]any{
+			"duration":       float64(30000),
+			"program":        testProgram,
+			"condition":      ConditionAboveOrEqual,
+			"threshold":      "0.5",
+			"stateCheckMode": stateCheckModeAtLeastOnce,
+			"failEarly":      false,
+			"resolution":     float64(10000),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Prepare() returned error: %v", err)
+	}
+	if state.Program != testProgram || state.Condition != ConditionAboveOrEqual || state.Threshold != 0.5 {
+		t.Errorf("Unexpected state %+v", state)
+	}
+	if state.Resolution != 10*time.Second || state.FailEarly || state.StateCheckMode != stateCheckModeAtLeastOnce {
+		t.Errorf("Unexpected state %+v", state)
+	}
+	if state.End.Sub(state.Start) != 30*time.Second {
+		t.Errorf("Expected a duration of 30s, got %v", state.End.Sub(state.Start))
+	}
+}
+
+func TestPrepare_RejectsInvalidThreshold(t *testing.T) {
+	action := &SignalFlowCheckAction{}
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, actionApi.PrepareActionRequestBody{
+		Config: map[string]any{"duration": float64(30000), "program": testProgram, "threshold": "fast"},
+	})
+	if err == nil {
+		t.Error("Expected an error for a threshold that isn't a number")
+	}
+}
+
+func TestSignalFlowCheckStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		values         []float64
+		stateCheckMode string
+		failEarly      bool
+		end            time.Time
+		wantError      bool
+		wantCompleted  bool
+	}{
+		{name: "all the time met", values: []float64{100, 200}, stateCheckMode: stateCheckModeAllTheTime, failEarly: true, end: time.Now().Add(time.Minute)},
+		{name: "all the time violated fails early", values: []float64{100, 700}, stateCheckMode: stateCheckModeAllTheTime, failEarly: true, end: time.Now().Add(time.Minute), wantError: true},
+		{name: "all the time violated fails at end", values: []float64{100, 700}, stateCheckMode: stateCheckModeAllTheTime, failEarly: false, end: time.Now().Add(time.Minute)},
+		{name: "at least once met", values: []float64{700, 100}, stateCheckMode: stateCheckModeAtLeastOnce, end: time.Now().Add(-time.Second), wantCompleted: true},
+		{name: "at least once never met", values: []float64{700, 800}, stateCheckMode: stateCheckModeAtLeastOnce, end: time.Now().Add(-time.Second), wantError: true, wantCompleted: true},
+		{name: "no data", stateCheckMode: stateCheckModeAllTheTime, end: time.Now().Add(-time.Second), wantError: true, wantCompleted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(tt.values...)
+			defer server.Close()
+			state := newState(tt.stateCheckMode, tt.failEarly, tt.end)
+
+			result, err := SignalFlowCheckStatus(context.Background(), state, resty.New().SetBaseURL(server.URL))
+			if err != nil {
+				t.Fatalf("SignalFlowCheckStatus() returned error: %v", err)
+			}
+			if (result.Error != nil) != tt.wantError {
+				t.Errorf("Expected error %v, got %v", tt.wantError, result.Error)
+			}
+			if result.Completed != tt.wantCompleted {
+				t.Errorf("Expected completed %v, got %v", tt.wantCompleted, result.Completed)
+			}
+			if len(*result.Metrics) != len(tt.values) {
+				t.Errorf("Expected %d metrics, got %d", len(tt.values), len(*result.Metrics))
+			}
+			if executions := server.Executions(); len(executions) != 1 || executions[0].Program != testProgram || !executions[0].Immediate {
+				t.Errorf("Expected the program to be executed immediately, got %+v", executions)
+			}
+		})
+	}
+}
+
+func TestSignalFlowCheckStatus_FailsAtEndAfterDeviation(t *testing.T) {
+	server := newTestServer(100, 700)
+	defer server.Close()
+	client := resty.New().SetBaseURL(server.URL)
+	state := newState(stateCheckModeAllTheTime, false, time.Now().Add(time.Minute))
+
+	if result, _ := SignalFlowCheckStatus(context.Background(), state, client); result.Error != nil {
+		t.Fatalf("Expected no error before the end of the step, got %v", result.Error)
+	}
+	if state.LastTimestamp == 0 {
+		t.Error("Expected the last timestamp to be remembered")
+	}
+
+	state.End = time.Now().Add(-time.Second)
+	result, _ := SignalFlowCheckStatus(context.Background(), state, client)
+	if result.Error == nil || !strings.Contains(result.Error.Title, "was not below 500") {
+		t.Errorf("Expected the deviation to be reported at the end, got %v", result.Error)
+	}
+	if executions := server.Executions(); executions[1].Start != state.LastTimestamp+1 {
+		t.Errorf("Expected the second execution to continue after the last value, got %+v", executions[1])
+	}
+}
+
+func TestSignalFlowCheckStatus_LeavesRecentValuesForLaterCalls(t *testing.T) {
+	now := time.Now()
+	server := signalflowtest.NewServer().
+		AddSeries("AAA", map[string]any{"sf_metric": "latency"}).
+		AddData(now.Add(-30*time.Second).UnixMilli(), map[string]float64{"AAA": 100}).
+		AddData(now.Add(-2*time.Second).UnixMilli(), map[string]float64{"AAA": 700})
+	defer server.Close()
+	client := resty.New().SetBaseURL(server.URL)
+	state := newState(stateCheckModeAllTheTime, true, now.Add(time.Minute))
+	state.Resolution = 10 * time.Second
+
+	result, _ := SignalFlowCheckStatus(context.Background(), state, client)
+	if result.Error != nil || len(*result.Metrics) != 1 {
+		t.Fatalf("Expected only the settled value to be evaluated, got %+v", result)
+	}
+	if stop := server.Executions()[0].Stop; stop > time.Now().Add(-10*time.Second).UnixMilli() {
+		t.Errorf("Expected the window to end a resolution before now, got %d", stop)
+	}
+
+	state.End = time.Now()
+	result, _ = SignalFlowCheckStatus(context.Background(), state, client)
+	if result.Error == nil || !strings.Contains(result.Error.Title, "700") {
+		t.Errorf("Expected the recent value to be evaluated by a later call, got %+v", result)
+	}
+}
+
+func TestSignalFlowCheckStatus_ToleratesApiErrors(t *testing.T) {
+	original := config.Config
+	defer func() { config.Config = original }()
+	config.Config.CheckApiErrorTolerance = 1
+
+	server := newTestServer().RespondWithStatus(http.StatusServiceUnavailable)
+	defer server.Close()
+	client := resty.New().SetBaseURL(server.URL)
+	state := newState(stateCheckModeAllTheTime, true, time.Now().Add(time.Minute))
+
+	if result, _ := SignalFlowCheckStatus(context.Background(), state, client); result.Error != nil || result.Completed {
+		t.Errorf("Expected the first API error to be tolerated, got %+v", result)
+	}
+	result, _ := SignalFlowCheckStatus(context.Background(), state, client)
+	if result.Error == nil || *result.Error.Status != actionApi.Errored {
+		t.Errorf("Expected the check to error once the tolerance is exceeded, got %+v", result)
+	}
+}
+
+func TestSignalFlowCheckStatus_ErrorsOnProgramErrors(t *testing.T) {
+	server := newTestServer().FailProgram("unknown function 'dta'")
+	defer server.Close()
+	state := newState(stateCheckModeAllTheTime, true, time.Now().Add(time.Minute))
+
+	result, _ := SignalFlowCheckStatus(context.Background(), state, resty.New().SetBaseURL(server.URL))
+	if result.Error == nil || !result.Completed || !strings.Contains(result.Error.Title, "unknown function 'dta'") {
+		t.Errorf("Expected the program error to end the check, got %+v", result)
+	}
+}
+
+func TestSeriesLabel(t *testing.T) {
+	point := signalflow.Point{TsId: "AAA", Metadata: signalflow.Metadata{Properties: map[string]any{"sf_metric": "latency", "sf_key": []any{"sf_metric", "service"}, "service": "checkout", "host": "a"}}}
+	if label := seriesLabel(point); label != "latency {service=checkout}" {
+		t.Errorf("Unexpected label %s", label)
+	}
+	if label := seriesLabel(signalflow.Point{TsId: "AAA"}); label != "AAA" {
+		t.Errorf("Expected the TSID for unknown series, got %s", label)
+	}
+}
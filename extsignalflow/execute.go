@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 steadybit GmbH. All rights reserved.
+ */
+
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2025 Steadybit GmbH
+
+package extsignalflow
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extclient/signalflow"
+)
+
+// seriesValue is the value of one time series at one logical timestamp.
+type seriesValue struct {
+	Series    string
+	Timestamp int64
+	Value     float64
+}
+
+// executeProgram runs the program for the time range between start and stop and returns the values of all published
+// time series. The computation is executed immediately, so it ends once the time range is computed.
+func executeProgram(ctx context.Context, client *resty.Client, program string, start time.Time, stop time.Time, resolution time.Duration) ([]seriesValue, error) {
+	if config.Config.ApiRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Config.ApiRequestTimeout)
+		defer cancel()
+	}
+
+	computation, err := signalflow.NewClient(client).Execute(ctx, program, signalflow.Options{
+		Start:      start,
+		Stop:       stop,
+		Resolution: resolution,
+		Immediate:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Only stops the job in Splunk if it didn't end, e.g. because the timeout interrupted it.
+	defer computation.Stop()
+
+	batches, err := computation.Collect()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []seriesValue
+	for _, batch := range batches {
+		for _, point := range batch.Points {
+			values = append(values, seriesValue{Series: seriesLabel(point), Timestamp: batch.TimestampMs, Value: point.Value})
+		}
+	}
+	return values, nil
+}
+
+// seriesLabel names a time series by its metric and dimensions, e.g. "service.request.duration {sf_service=checkout}".
+func seriesLabel(point signalflow.Point) string {
+	metric := point.Metadata.Metric()
+	dimensions := point.Metadata.Dimensions()
+	if len(dimensions) == 0 {
+		if metric == "" {
+			return point.TsId
+		}
+		return metric
+	}
+
+	pairs := make([]string, 0, len(dimensions))
+	for _, name := range slices.Sorted(maps.Keys(dimensions)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, dimensions[name]))
+	}
+	if metric == "" {
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprintf("%s {%s}", metric, strings.Join(pairs, ","))
+}
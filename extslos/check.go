@@ -52,6 +52,8 @@ type SloCheckState struct {
 	// that a deviating state was observed during the step so the failure can be reported once the step ends.
 	DeviationSeen  bool
 	DeviationTitle string
+	// ConsecutiveApiErrors counts the Splunk API calls in a row that didn't respond with a success status.
+	ConsecutiveApiErrors int
 }
 
 func NewSloStateCheckAction() action_kit_sdk.Action[SloCheckState] {
@@ -287,21 +289,36 @@ func SLOCheckStatus(ctx context.Context, state *SloCheckState, client *resty.Cli
 		return nil, new(extension_kit.ToError(fmt.Sprintf("Failed to retrieve SLOs from Splunk for ID %s. Full response: %v", state.SloID, res.String()), err))
 	}
 
+	completed := now.After(state.End)
+
 	if !res.IsSuccess() {
-		log.Err(err).Msgf("Splunk API responded with unexpected status code %d while retrieving SLOs for ID %s. Full response: %v", res.StatusCode(), state.SloID, res.String())
-	} else {
-		if state.CheckNewAlertsOnly {
-			var filteredSlos []Slo
-			for _, slo := range slosFound.Results {
-				if time.UnixMilli(slo.LastUpdated).After(state.Start) {
-					filteredSlos = append(filteredSlos, slo)
-				}
+		state.ConsecutiveApiErrors++
+		log.Warn().Msgf("Splunk API responded with unexpected status code %d while retrieving SLOs for ID %s (%d consecutive errors). Full response: %v", res.StatusCode(), state.SloID, state.ConsecutiveApiErrors, res.String())
+		// Never evaluate the expected state without search results, as an empty result would look like "no alerts".
+		if state.ConsecutiveApiErrors > config.Config.CheckApiErrorTolerance || completed {
+			return &action_kit_api.StatusResult{
+				Completed: true,
+				Error: new(action_kit_api.ActionKitError{
+					Title: fmt.Sprintf("Splunk API responded with status code %d while retrieving SLO '%s': %s",
+						res.StatusCode(), state.SloName, strings.TrimSpace(res.String())),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+	state.ConsecutiveApiErrors = 0
+
+	if state.CheckNewAlertsOnly {
+		var filteredSlos []Slo
+		for _, slo := range slosFound.Results {
+			if time.UnixMilli(slo.LastUpdated).After(state.Start) {
+				filteredSlos = append(filteredSlos, slo)
 			}
-			slosFound.Results = filteredSlos
 		}
+		slosFound.Results = filteredSlos
 	}
 
-	completed := now.After(state.End)
 	var checkError *action_kit_api.ActionKitError
 
 	if state.StateCheckMode == stateCheckModeAllTheTime {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-resty/resty/v2"
 	actionApi "github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-splunk/config"
 )
 
 // TestDescribe verifies that the action description is built as expected.
@@ -339,3 +340,50 @@ func TestToMetric(t *testing.T) {
 		t.Errorf("Expected metric state 'danger' for breach alerts, got '%s'", metric2.Metric["state"])
 	}
 }
+
+// TestStatus_ApiErrorTolerance verifies that non-2xx responses are tolerated up to the configured limit and then error the step.
+func TestStatus_ApiErrorTolerance(t *testing.T) {
+	ts := newTestServer([]byte(`{"code":503,"message":"Service Unavailable"}`), http.StatusServiceUnavailable)
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalTolerance := config.Config.CheckApiErrorTolerance
+	config.Config.CheckApiErrorTolerance = 1
+	defer func() {
+		config.Config.CheckApiErrorTolerance = originalTolerance
+	}()
+
+	now := time.Now()
+	state := SloCheckState{
+		SloID:          "slo1",
+		SloName:        "SLO One",
+		Start:          now.Add(-time.Minute),
+		End:            now.Add(time.Minute),
+		ExpectedState:  noAlerts,
+		StateCheckMode: stateCheckModeAllTheTime,
+		FailEarly:      true,
+	}
+
+	statusResult, err := SLOCheckStatus(context.Background(), &state, RestyClient)
+	if err != nil {
+		t.Fatalf("SLOCheckStatus returned error: %v", err)
+	}
+	if statusResult.Error != nil || statusResult.Completed {
+		t.Errorf("Expected the first API error to be tolerated, got %+v", statusResult)
+	}
+
+	statusResult, err = SLOCheckStatus(context.Background(), &state, RestyClient)
+	if err != nil {
+		t.Fatalf("SLOCheckStatus returned error: %v", err)
+	}
+	if statusResult.Error == nil {
+		t.Fatal("Expected an error once the tolerance is exceeded")
+	}
+	if *statusResult.Error.Status != actionApi.Errored {
+		t.Errorf("Expected status %s, got %s", actionApi.Errored, *statusResult.Error.Status)
+	}
+	if !strings.Contains(statusResult.Error.Title, "503") || !strings.Contains(statusResult.Error.Title, "Service Unavailable") {
+		t.Errorf("Expected the title to contain the status code and Splunk error, got: %s", statusResult.Error.Title)
+	}
+}
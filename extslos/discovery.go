@@ -9,6 +9,7 @@ package extslos
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/discovery-kit/go/discovery_kit_api"
@@ -28,6 +29,8 @@ const (
 	attributeID               = "splunk.slo.id"
 	attributeIndicator        = "splunk.slo.indicator"
 	attributeCreator          = "splunk.slo.creator"
+	defaultPageSize           = 100
+	defaultMaxResults         = 10000
 )
 
 type sloDiscovery struct {
@@ -109,45 +112,70 @@ func (d *sloDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescrip
 }
 
 func (d *sloDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_api.Target, error) {
-	return getAllSLOs(ctx, RestyClient), nil
+	return getAllSLOs(ctx, RestyClient)
 }
 
-func getAllSLOs(ctx context.Context, client *resty.Client) []discovery_kit_api.Target {
+// getAllSLOs retrieves the SLOs page by page, following the cursor returned with every page. If a page can't be
+// retrieved, an error is returned instead of the SLOs found so far, so the cached discovery keeps the previous, complete
+// result.
+func getAllSLOs(ctx context.Context, client *resty.Client) ([]discovery_kit_api.Target, error) {
 	result := make([]discovery_kit_api.Target, 0, 1000)
 
-	var splunkResponse Response
-	res, err := client.R().
-		SetContext(ctx).
-		SetResult(&splunkResponse).
-		SetBody(`{}`).
-		Post("/v2/slo/search")
-
-	if err != nil {
-		log.Err(err).Msgf("Failed to retrieve detectors from Splunk. Full response: %v", res.String())
-		return result
+	pageSize := config.Config.DiscoveryPageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	maxResults := config.Config.DiscoveryMaxResults
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
 	}
 
-	if res.StatusCode() != 200 && res.StatusCode() != 404 {
-		log.Warn().Msgf("Splunk API responded with unexpected status code %d while retrieving slos. Full response: %v",
-			res.StatusCode(),
-			res.String())
-		return result
-	} else {
+	cursor := ""
+	for len(result) < maxResults {
+		var splunkResponse Response
+		res, err := client.R().
+			SetContext(ctx).
+			SetResult(&splunkResponse).
+			SetBody(SLOSearchPage{
+				Limit:  min(pageSize, maxResults-len(result)),
+				Cursor: cursor,
+			}).
+			Post("/v2/slo/search")
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve slos from Splunk: %w", err)
+		}
+
+		if res.StatusCode() != 200 && res.StatusCode() != 404 {
+			return nil, fmt.Errorf("splunk API responded with unexpected status code %d while retrieving slos: %s", res.StatusCode(), res.String())
+		}
+
 		log.Trace().Msgf("Splunk response: %v", splunkResponse)
 
-		for _, detector := range splunkResponse.Results {
+		for _, slo := range splunkResponse.Results {
 			result = append(result, discovery_kit_api.Target{
-				Id:         detector.ID,
+				Id:         slo.ID,
 				TargetType: TargetType,
-				Label:      detector.Name,
+				Label:      slo.Name,
 				Attributes: map[string][]string{
-					attributeID:        {detector.ID},
-					attributeName:      {detector.Name},
-					attributeIndicator: {detector.Indicator},
-					attributeCreator:   {detector.Creator},
+					attributeID:        {slo.ID},
+					attributeName:      {slo.Name},
+					attributeIndicator: {slo.Indicator},
+					attributeCreator:   {slo.Creator},
 				}})
 		}
+
+		// The last page comes without a cursor to the next one. Empty pages and reaching the total count reported by Splunk
+		// end the pagination as well.
+		if splunkResponse.NextCursor == "" || len(splunkResponse.Results) == 0 || (splunkResponse.Count > 0 && len(result) >= splunkResponse.Count) {
+			break
+		}
+		cursor = splunkResponse.NextCursor
+	}
+
+	if len(result) >= maxResults {
+		log.Warn().Msgf("Reached the maximum of %d slos during discovery, remaining slos are ignored.", maxResults)
 	}
 
-	return discovery_kit_commons.ApplyAttributeExcludes(result, config.Config.DiscoveryAttributesExcludesSLO)
+	return discovery_kit_commons.ApplyAttributeExcludes(result, config.Config.DiscoveryAttributesExcludesSLO), nil
 }
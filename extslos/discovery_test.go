@@ -3,9 +3,13 @@ package extslos
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/go-resty/resty/v2"
@@ -112,7 +116,7 @@ func TestDiscoverTargets_ValidResponse(t *testing.T) {
 	}
 }
 
-// TestDiscoverTargets_UnexpectedStatus tests the behavior when the Splunk API returns an unexpected status code.
+// TestDiscoverTargets_UnexpectedStatus tests that an unexpected status code of the Splunk API is reported as an error.
 func TestDiscoverTargets_UnexpectedStatus(t *testing.T) {
 	// Create a test server that returns a 500 error.
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -126,8 +130,8 @@ func TestDiscoverTargets_UnexpectedStatus(t *testing.T) {
 
 	d := &sloDiscovery{}
 	targets, err := d.DiscoverTargets(context.Background())
-	if err != nil {
-		t.Errorf("DiscoverTargets returned error: %v", err)
+	if err == nil {
+		t.Error("DiscoverTargets should return an error for an unexpected status code")
 	}
 	if len(targets) != 0 {
 		t.Errorf("DiscoverTargets returned %d targets; want 0", len(targets))
@@ -164,15 +168,15 @@ func (e *simulateClientErrorRoundTripper) RoundTrip(req *http.Request) (*http.Re
 	return nil, errors.New("forced error")
 }
 
-// TestDiscoverTargets_ClientError tests that an error during the HTTP call is handled gracefully.
+// TestDiscoverTargets_ClientError tests that an error during the HTTP call is reported.
 func TestDiscoverTargets_ClientError(t *testing.T) {
 	client := resty.New().SetTransport(&simulateClientErrorRoundTripper{})
 	RestyClient = client
 
 	d := &sloDiscovery{}
 	targets, err := d.DiscoverTargets(context.Background())
-	if err != nil {
-		t.Errorf("DiscoverTargets returned error: %v", err)
+	if err == nil {
+		t.Error("DiscoverTargets should return an error if the request fails")
 	}
 	if len(targets) != 0 {
 		t.Errorf("DiscoverTargets returned %d targets; want 0", len(targets))
@@ -216,3 +220,95 @@ func TestDiscoverTargets_AttributeExclusion(t *testing.T) {
 	// Restore the original exclusion configuration.
 	config.Config.DiscoveryAttributesExcludesSLO = originalExcludes
 }
+
+// TestDiscoverTargets_Pagination verifies that SLO search follows the cursor of every page until the last page.
+func TestDiscoverTargets_Pagination(t *testing.T) {
+	slos := make([]Slo, 0, 5)
+	for i := 0; i < 5; i++ {
+		slos = append(slos, Slo{ID: fmt.Sprintf("slo%d", i), Name: fmt.Sprintf("SLO %d", i)})
+	}
+
+	var cursors []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page SLOSearchPage
+		if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+			t.Errorf("failed to decode search body: %v", err)
+		}
+		cursors = append(cursors, page.Cursor)
+		start := 0
+		if page.Cursor != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(page.Cursor, "page-"))
+		}
+		end := min(start+page.Limit, len(slos))
+		response := Response{Count: len(slos), Results: slos[start:end]}
+		if end < len(slos) {
+			response.NextCursor = fmt.Sprintf("page-%d", end)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalPageSize := config.Config.DiscoveryPageSize
+	config.Config.DiscoveryPageSize = 2
+	defer func() {
+		config.Config.DiscoveryPageSize = originalPageSize
+	}()
+
+	d := &sloDiscovery{}
+	targets, err := d.DiscoverTargets(context.Background())
+	if err != nil {
+		t.Errorf("DiscoverTargets returned error: %v", err)
+	}
+	if len(targets) != 5 {
+		t.Errorf("DiscoverTargets returned %d targets; want 5", len(targets))
+	}
+	if fmt.Sprint(cursors) != "[ page-2 page-4]" {
+		t.Errorf("Requested cursors = %q; want the cursors returned with the previous pages", cursors)
+	}
+	for i, target := range targets {
+		if target.Id != fmt.Sprintf("slo%d", i) {
+			t.Errorf("Target[%d].Id = %s; want slo%d", i, target.Id, i)
+		}
+	}
+}
+
+// TestDiscoverTargets_PaginationFailsOnError verifies that a failing page fails the discovery instead of returning the
+// SLOs found so far.
+func TestDiscoverTargets_PaginationFailsOnError(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{Count: 4, Results: []Slo{{ID: "slo0"}, {ID: "slo1"}}, NextCursor: "page-2"})
+	}))
+	defer ts.Close()
+
+	RestyClient = resty.New().SetBaseURL(ts.URL)
+
+	originalPageSize := config.Config.DiscoveryPageSize
+	config.Config.DiscoveryPageSize = 2
+	defer func() {
+		config.Config.DiscoveryPageSize = originalPageSize
+	}()
+
+	d := &sloDiscovery{}
+	targets, err := d.DiscoverTargets(context.Background())
+	if err == nil {
+		t.Error("DiscoverTargets should return an error if a page can't be retrieved")
+	}
+	if len(targets) != 0 {
+		t.Errorf("DiscoverTargets returned %d targets; want none instead of a partial result", len(targets))
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
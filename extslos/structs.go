@@ -1,8 +1,9 @@
 package extslos
 
 type Response struct {
-	Count   int   `json:"count"`
-	Results []Slo `json:"results"`
+	Count      int    `json:"count"`
+	Results    []Slo  `json:"results"`
+	NextCursor string `json:"nextCursor"`
 }
 
 type Slo struct {
@@ -60,3 +61,8 @@ type SLOSearchConfig struct {
 	ErrorBudgetLeftAlertsTriggered bool     `json:"errorBudgetLeftAlertsTriggered"`
 	SLOIds                         []string `json:"sloIds"`
 }
+
+type SLOSearchPage struct {
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor,omitempty"`
+}
@@ -5,10 +5,10 @@
 package main
 
 import (
-	"strings"
+	"context"
+	"time"
 
 	_ "github.com/KimMachineGun/automemlimit" // By default, it sets `GOMEMLIMIT` to 90% of cgroup's memory limit.
-	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk"
@@ -23,17 +23,15 @@ import (
 	"github.com/steadybit/extension-kit/extruntime"
 	"github.com/steadybit/extension-kit/extsignals"
 	"github.com/steadybit/extension-splunk/config"
+	"github.com/steadybit/extension-splunk/extclient"
 	"github.com/steadybit/extension-splunk/extdetectors"
 	"github.com/steadybit/extension-splunk/extevents"
+	"github.com/steadybit/extension-splunk/extmutingrules"
+	"github.com/steadybit/extension-splunk/extsignalflow"
 	"github.com/steadybit/extension-splunk/extslos"
 	_ "go.uber.org/automaxprocs" // Importing automaxprocs automatically adjusts GOMAXPROCS.
 )
 
-const (
-	contentType         = "Content-Type"
-	applciationJsonType = "application/json"
-)
-
 func main() {
 	extlogging.InitZeroLog()
 
@@ -46,21 +44,30 @@ func main() {
 	config.ParseConfiguration()
 	config.ValidateConfiguration()
 	initRestyClient()
+	validateConnectivity()
 
 	discovery_kit_sdk.Register(extdetectors.NewDetectorDiscovery())
 	action_kit_sdk.RegisterAction(extdetectors.NewDetectorStateCheckAction())
+	action_kit_sdk.RegisterAction(extdetectors.NewDetectorMuteAction())
+	action_kit_sdk.RegisterAction(extdetectors.NewDetectorDisableRulesAction())
 	extevents.RegisterEventListenerHandlers()
 
+	discovery_kit_sdk.Register(extmutingrules.NewMutingRuleDiscovery())
+
 	discovery_kit_sdk.Register(extslos.NewSLODiscovery())
 	action_kit_sdk.RegisterAction(extslos.NewSloStateCheckAction())
 
+	action_kit_sdk.RegisterAction(extsignalflow.NewSignalFlowCheckAction())
+
 	exthttp.RegisterRevisionedHandler("/", getExtensionList)
+	exthttp.RegisterHttpHandler("/health/splunk", exthttp.GetterAsHandler(extclient.GetHealthReport))
+	exthttp.RegisterHttpHandler("/health/splunk/events", exthttp.GetterAsHandler(extevents.GetDeliveryStats))
 
 	extsignals.ActivateSignalHandlers()
 
 	action_kit_sdk.RegisterCoverageEndpoints()
 
-	exthealth.SetReady(true)
+	extclient.ActivateReadiness()
 
 	exthttp.Listen(exthttp.ListenOpts{
 		Port: 8083,
@@ -77,20 +84,29 @@ type ExtensionListResponse struct {
 }
 
 func initRestyClient() {
-	extdetectors.RestyClient = resty.New()
-	extdetectors.RestyClient.SetBaseURL(strings.TrimRight(config.Config.ApiBaseUrl, "/"))
-	extdetectors.RestyClient.SetHeader("Authorization", "Bearer "+config.Config.AccessToken)
-	extdetectors.RestyClient.SetHeader(contentType, applciationJsonType)
-
-	extslos.RestyClient = resty.New()
-	extslos.RestyClient.SetBaseURL(strings.TrimRight(config.Config.ApiBaseUrl, "/"))
-	extslos.RestyClient.SetHeader("Authorization", "Bearer "+config.Config.AccessToken)
-	extslos.RestyClient.SetHeader(contentType, applciationJsonType)
-
-	extevents.RestyClient = resty.New()
-	extevents.RestyClient.SetBaseURL(strings.TrimRight(config.Config.IngestBaseUrl, "/"))
-	extevents.RestyClient.SetHeader("X-SF-Token", config.Config.AccessToken)
-	extevents.RestyClient.SetHeader(contentType, applciationJsonType)
+	apiClient := extclient.NewApiClient()
+	extdetectors.RestyClient = apiClient
+	extmutingrules.RestyClient = apiClient
+	extslos.RestyClient = apiClient
+	extsignalflow.RestyClient = extclient.NewStreamClient()
+	extevents.RestyClient = extclient.NewIngestClient()
+	if config.Config.HecEnabled {
+		extevents.HecRestyClient = extclient.NewHecClient()
+	}
+}
+
+func validateConnectivity() {
+	if config.Config.StartupValidation == config.StartupValidationOff {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ingestClient := extevents.RestyClient
+	if !config.Config.ObservabilityEventsEnabled && !config.Config.TraceExportEnabled && !config.Config.MetricExportEnabled {
+		// None of the enabled sinks posts to the ingest API, so the token doesn't need the "Ingest" permission.
+		ingestClient = nil
+	}
+	config.ReportStartupValidation(extclient.ValidateConnectivity(ctx, extdetectors.RestyClient, ingestClient))
 }
 
 func getExtensionList() ExtensionListResponse {
@@ -98,33 +114,7 @@ func getExtensionList() ExtensionListResponse {
 		ActionList:    action_kit_sdk.GetActionList(),
 		DiscoveryList: discovery_kit_sdk.GetDiscoveryList(),
 		EventListenerList: event_kit_api.EventListenerList{
-			EventListeners: []event_kit_api.EventListener{
-				{
-					Method:   "POST",
-					Path:     "/events/experiment-started",
-					ListenTo: []string{"experiment.execution.created"},
-				},
-				{
-					Method:   "POST",
-					Path:     "/events/experiment-completed",
-					ListenTo: []string{"experiment.execution.completed", "experiment.execution.failed", "experiment.execution.canceled", "experiment.execution.errored"},
-				},
-				{
-					Method:   "POST",
-					Path:     "/events/experiment-step-started",
-					ListenTo: []string{"experiment.execution.step-started"},
-				},
-				{
-					Method:   "POST",
-					Path:     "/events/experiment-target-started",
-					ListenTo: []string{"experiment.execution.target-started"},
-				},
-				{
-					Method:   "POST",
-					Path:     "/events/experiment-target-completed",
-					ListenTo: []string{"experiment.execution.target-completed", "experiment.execution.target-canceled", "experiment.execution.target-errored", "experiment.execution.target-failed"},
-				},
-			},
+			EventListeners: extevents.GetEventListeners(),
 		},
 	}
 }